@@ -0,0 +1,260 @@
+package api
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Source statuses
+const (
+	STATUS_OK Status = iota
+	STATUS_FAILED
+	STATUS_RATE_LIMITED
+	STATUS_SKIPPED
+	STATUS_TRUNCATED
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	// ErrNoToken is returned by sources which can't be used without API token
+	// if token is not set
+	ErrNoToken = errors.New("API token is required")
+
+	// ErrRateLimited is returned by sources if API rate limit is exceeded
+	ErrRateLimited = errors.New("rate limit exceeded")
+
+	// ErrTruncated is returned with partial results by sources which stopped
+	// fetching results because of configured limit or failed to fetch some
+	// of them
+	ErrTruncated = errors.New("results are truncated")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Source is passive subdomains source
+type Source interface {
+	// Name returns human-readable source name
+	Name() string
+
+	// NeedsToken returns true if source can't be used without API token and
+	// token is not set
+	NeedsToken() bool
+
+	// Find tries to find subdomains of given domain
+	Find(ctx context.Context, domain string) (Subdomains, error)
+}
+
+// Status is source search status
+type Status uint8
+
+// Subdomain contains info about found subdomain
+type Subdomain struct {
+	Name      string
+	FirstSeen time.Time // Date when subdomain was seen for the first time (optional)
+	LastSeen  time.Time // Date when subdomain was seen for the last time (optional)
+	Issuers   []string  // Names of certificate issuers (optional)
+}
+
+// Subdomains is a slice with subdomains
+type Subdomains []*Subdomain
+
+// Result contains result of search using one source
+type Result struct {
+	Source     Source
+	Subdomains Subdomains
+	Error      error
+	Duration   time.Duration
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// sources is a slice with all registered sources
+var sources []Source
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Register adds given source to registry
+func Register(src Source) {
+	if src == nil {
+		return
+	}
+
+	sources = append(sources, src)
+}
+
+// Sources returns all registered sources
+func Sources() []Source {
+	return sources
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Search concurrently searches subdomains using all registered sources. Every source
+// has its own timeout, all sources share given context. Results are sent to returned
+// channel as soon as they arrive, channel is closed when all sources are done.
+// Sources without required API token are skipped.
+func Search(ctx context.Context, domain string, timeout time.Duration) <-chan *Result {
+	var wg sync.WaitGroup
+
+	resultChan := make(chan *Result, len(sources))
+
+	for _, src := range sources {
+		if src.NeedsToken() {
+			resultChan <- &Result{Source: src, Error: ErrNoToken}
+			continue
+		}
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+			resultChan <- search(ctx, src, domain, timeout)
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(resultChan)
+	}()
+
+	return resultChan
+}
+
+// StatusError returns error for given non-ok HTTP status code of source API
+func StatusError(source string, statusCode int) error {
+	if statusCode == 429 {
+		return fmt.Errorf("%s API returned non-ok status code %d: %w", source, statusCode, ErrRateLimited)
+	}
+
+	return fmt.Errorf("%s API returned non-ok status code %d", source, statusCode)
+}
+
+// RequestTimeout returns timeout for HTTP request which must be finished before
+// context deadline. It returns 0 (no timeout) if context has no deadline.
+func RequestTimeout(ctx context.Context) time.Duration {
+	deadline, ok := ctx.Deadline()
+
+	if !ok {
+		return 0
+	}
+
+	return max(time.Until(deadline), time.Millisecond)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Status returns search status
+func (r *Result) Status() Status {
+	switch {
+	case r == nil || r.Error == nil:
+		return STATUS_OK
+	case errors.Is(r.Error, ErrNoToken):
+		return STATUS_SKIPPED
+	case errors.Is(r.Error, ErrRateLimited):
+		return STATUS_RATE_LIMITED
+	case errors.Is(r.Error, ErrTruncated):
+		return STATUS_TRUNCATED
+	}
+
+	return STATUS_FAILED
+}
+
+// Seen updates first and last seen dates using given date
+func (s *Subdomain) Seen(date time.Time) {
+	if s == nil || date.IsZero() {
+		return
+	}
+
+	if s.FirstSeen.IsZero() || date.Before(s.FirstSeen) {
+		s.FirstSeen = date
+	}
+
+	if s.LastSeen.IsZero() || date.After(s.LastSeen) {
+		s.LastSeen = date
+	}
+}
+
+// AddIssuer adds certificate issuer name to subdomain info
+func (s *Subdomain) AddIssuer(issuer string) {
+	if s == nil || issuer == "" || slices.Contains(s.Issuers, issuer) {
+		return
+	}
+
+	s.Issuers = append(s.Issuers, issuer)
+}
+
+// Names returns names of all subdomains
+func (s Subdomains) Names() []string {
+	var result []string
+
+	for _, sd := range s {
+		result = append(result, sd.Name)
+	}
+
+	return result
+}
+
+// IsValidName returns true if given name is valid subdomain of given domain
+func IsValidName(name, domain string) bool {
+	switch {
+	case name == "",
+		strings.HasPrefix(name, "*"),
+		strings.Contains(name, "@"),
+		strings.Contains(name, " "):
+		return false
+	}
+
+	return name == domain || strings.HasSuffix(name, "."+domain)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// search searches subdomains using given source
+func search(ctx context.Context, src Source, domain string, timeout time.Duration) *Result {
+	start := time.Now()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	findChan := make(chan *Result, 1)
+
+	// Source may ignore context, so we run it in a separate goroutine to
+	// be able to stop waiting for it
+	go func() {
+		subdomains, err := src.Find(ctx, domain)
+		findChan <- &Result{Source: src, Subdomains: subdomains, Error: err}
+	}()
+
+	var result *Result
+
+	select {
+	case result = <-findChan:
+	case <-ctx.Done():
+		result = &Result{
+			Source: src,
+			Error:  fmt.Errorf("%s didn't respond in time: %w", src.Name(), ctx.Err()),
+		}
+	}
+
+	result.Duration = time.Since(start)
+
+	return result
+}
@@ -8,17 +8,45 @@ package certspotter
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/essentialkaos/ek/v13/req"
+
+	"github.com/essentialkaos/subdy/api"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// API_URL is URL of CertSpotter issuances API endpoint
+const API_URL = "https://api.certspotter.com/v1/issuances"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Source is CertSpotter API source
+type Source struct {
+	Token    string // API token (optional)
+	MaxPages int    // Maximum number of pages to fetch (0 = no limit)
+	History  bool   // Request full issuance history with issuers info
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
 // cert contains cert info
 type cert struct {
-	DNSNames []string `json:"dns_names"`
+	ID        string    `json:"id"`
+	DNSNames  []string  `json:"dns_names"`
+	NotBefore time.Time `json:"not_before"`
+	Issuer    *issuer   `json:"issuer"`
+}
+
+// issuer contains info about certificate issuer
+type issuer struct {
+	Name         string `json:"name"`
+	FriendlyName string `json:"friendly_name"`
 }
 
 // certs is a slice of certs
@@ -26,21 +54,116 @@ type certs []*cert
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// Name returns source name
+func (s *Source) Name() string {
+	return "CertSpotter"
+}
+
+// NeedsToken returns true if source can't be used without API token
+func (s *Source) NeedsToken() bool {
+	return false
+}
+
 // Find tries to find subdomains using CertSpotter API
-func Find(domain, token string) ([]string, error) {
+func (s *Source) Find(ctx context.Context, domain string) (api.Subdomains, error) {
+	var after string
+	var subdomains api.Subdomains
+
+	index := map[string]*api.Subdomain{}
+
+	for page := 1; s.MaxPages <= 0 || page <= s.MaxPages; page++ {
+		if ctx.Err() != nil {
+			return subdomains, ctx.Err()
+		}
+
+		certs, err := s.fetchPage(ctx, domain, after)
+
+		if err != nil {
+			return subdomains, err
+		}
+
+		if len(certs) == 0 {
+			break
+		}
+
+		for _, cert := range certs {
+			for _, name := range cert.DNSNames {
+				name = strings.ToLower(strings.TrimSpace(name))
+
+				if !api.IsValidName(name, domain) {
+					continue
+				}
+
+				subdomain := index[name]
+
+				if subdomain == nil {
+					subdomain = &api.Subdomain{Name: name}
+					index[name] = subdomain
+					subdomains = append(subdomains, subdomain)
+				}
+
+				subdomain.Seen(cert.NotBefore)
+
+				if s.History {
+					subdomain.AddIssuer(cert.Issuer.String())
+				}
+			}
+		}
+
+		after = certs[len(certs)-1].ID
+
+		// The last page isn't empty, so there can be more issuances
+		if page == s.MaxPages {
+			return subdomains, fmt.Errorf(
+				"CertSpotter API pages limit (%d) reached: %w", s.MaxPages, api.ErrTruncated,
+			)
+		}
+	}
+
+	return subdomains, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// String returns issuer name
+func (i *issuer) String() string {
+	switch {
+	case i == nil:
+		return ""
+	case i.FriendlyName != "":
+		return i.FriendlyName
+	}
+
+	return i.Name
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// fetchPage fetches one page of issuances which goes after issuance with given ID
+func (s *Source) fetchPage(ctx context.Context, domain, after string) (certs, error) {
+	query := url.Values{}
+
+	query.Set("domain", domain)
+	query.Set("include_subdomains", "true")
+	query.Add("expand", "dns_names")
+
+	if s.History {
+		query.Add("expand", "issuer")
+	}
+
+	if after != "" {
+		query.Set("after", after)
+	}
+
 	r := req.Request{
-		URL: "https://api.certspotter.com/v1/issuances",
-		Query: req.Query{
-			"domain":             domain,
-			"include_subdomains": true,
-			"expand":             "dns_names",
-		},
+		URL:         API_URL + "?" + query.Encode(),
 		Accept:      req.CONTENT_TYPE_JSON,
+		Timeout:     api.RequestTimeout(ctx),
 		AutoDiscard: true,
 	}
 
-	if token != "" {
-		r.Auth = req.AuthBearer{token}
+	if s.Token != "" {
+		r.Auth = req.AuthBearer{s.Token}
 	}
 
 	resp, err := r.Get()
@@ -50,7 +173,7 @@ func Find(domain, token string) ([]string, error) {
 	}
 
 	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("CertSpotter API returned non-ok status code %d", resp.StatusCode)
+		return nil, api.StatusError("CertSpotter", resp.StatusCode)
 	}
 
 	certs := certs{}
@@ -60,17 +183,5 @@ func Find(domain, token string) ([]string, error) {
 		return nil, fmt.Errorf("Can't decode CertSpotter API response: %w", err)
 	}
 
-	var subdomains []string
-
-	for _, cert := range certs {
-		for _, subdomain := range cert.DNSNames {
-			if strings.HasPrefix(subdomain, "*") {
-				continue
-			}
-
-			subdomains = append(subdomains, subdomain)
-		}
-	}
-
-	return subdomains, nil
+	return certs, nil
 }
@@ -0,0 +1,178 @@
+package crtsh
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/req"
+
+	"github.com/essentialkaos/subdy/api"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// API_URL is URL of crt.sh search endpoint
+const API_URL = "https://crt.sh/"
+
+// DEFAULT_RETRIES is default number of retries
+const DEFAULT_RETRIES = 3
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Source is crt.sh source
+type Source struct {
+	Retries int // Number of retries on timeouts and server errors (default: 3)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// cert contains cert info
+type cert struct {
+	NameValue string   `json:"name_value"`
+	NotBefore dateTime `json:"not_before"`
+}
+
+// dateTime is date in crt.sh format (without timezone)
+type dateTime struct {
+	time.Time
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// errTemporary is temporary error which can be fixed by retrying request
+var errTemporary = errors.New("temporary error")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns source name
+func (s *Source) Name() string {
+	return "crt.sh"
+}
+
+// NeedsToken returns true if source can't be used without API token
+func (s *Source) NeedsToken() bool {
+	return false
+}
+
+// Find tries to find subdomains using crt.sh
+func (s *Source) Find(ctx context.Context, domain string) (api.Subdomains, error) {
+	var err error
+	var certs []*cert
+
+	retries := s.Retries
+
+	if retries <= 0 {
+		retries = DEFAULT_RETRIES
+	}
+
+	for attempt := 0; attempt <= retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * 2 * time.Second):
+			}
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		certs, err = fetchCerts(ctx, domain)
+
+		if err == nil || !errors.Is(err, errTemporary) {
+			break
+		}
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	var subdomains api.Subdomains
+
+	index := map[string]*api.Subdomain{}
+
+	for _, cert := range certs {
+		for _, name := range strings.Split(cert.NameValue, "\n") {
+			name = strings.ToLower(strings.TrimSpace(name))
+
+			if !api.IsValidName(name, domain) {
+				continue
+			}
+
+			subdomain := index[name]
+
+			if subdomain == nil {
+				subdomain = &api.Subdomain{Name: name}
+				index[name] = subdomain
+				subdomains = append(subdomains, subdomain)
+			}
+
+			subdomain.Seen(cert.NotBefore.Time)
+		}
+	}
+
+	return subdomains, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// UnmarshalJSON parses date in crt.sh format
+func (d *dateTime) UnmarshalJSON(data []byte) error {
+	value := strings.Trim(string(data), `"`)
+
+	if value == "" || value == "null" {
+		return nil
+	}
+
+	var err error
+
+	d.Time, err = time.Parse("2006-01-02T15:04:05", value)
+
+	return err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// fetchCerts fetches certificates info from crt.sh
+func fetchCerts(ctx context.Context, domain string) ([]*cert, error) {
+	resp, err := req.Request{
+		URL:         API_URL,
+		Query:       req.Query{"q": "%." + domain, "output": "json"},
+		Accept:      req.CONTENT_TYPE_JSON,
+		Timeout:     api.RequestTimeout(ctx),
+		AutoDiscard: true,
+	}.Get()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't send request to crt.sh: %w (%w)", err, errTemporary)
+	}
+
+	switch {
+	case resp.StatusCode == 502, resp.StatusCode == 503, resp.StatusCode == 504:
+		return nil, fmt.Errorf("%w (%w)", api.StatusError("crt.sh", resp.StatusCode), errTemporary)
+	case resp.StatusCode > 299:
+		return nil, api.StatusError("crt.sh", resp.StatusCode)
+	}
+
+	var certs []*cert
+
+	err = resp.JSON(&certs)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode crt.sh response: %w", err)
+	}
+
+	return certs, nil
+}
@@ -0,0 +1,315 @@
+package ctlog
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/essentialkaos/ek/v13/req"
+
+	"github.com/essentialkaos/subdy/api"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_MAX_ENTRIES is default number of the latest entries to fetch from every
+// log if range is not set
+const DEFAULT_MAX_ENTRIES = 10000
+
+// BATCH_SIZE is maximum number of entries requested by one get-entries request
+const BATCH_SIZE = 256
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Source is source which fetches data directly from RFC 6962 CT logs
+type Source struct {
+	LogList    string // Path to log list file (Chrome/Apple v3 JSON format)
+	Start      int64  // Index of the first entry (optional)
+	End        int64  // Index of the last entry (inclusive, optional)
+	MaxEntries int64  // Number of entries to fetch if range or its end is not set
+}
+
+// Log contains basic info about CT log
+type Log struct {
+	Description string    `json:"description"`
+	URL         string    `json:"url"`
+	State       *LogState `json:"state"`
+}
+
+// Entry contains log entry
+type Entry struct {
+	LeafInput []byte `json:"leaf_input"`
+	ExtraData []byte `json:"extra_data"`
+}
+
+// LogState contains info about log state
+type LogState struct {
+	Rejected *struct{} `json:"rejected"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// logList contains log list data
+type logList struct {
+	Operators []*struct {
+		Logs []*Log `json:"logs"`
+	} `json:"operators"`
+}
+
+// sth contains signed tree head info
+type sth struct {
+	TreeSize int64 `json:"tree_size"`
+}
+
+// entries contains get-entries response
+type entries struct {
+	Entries []*Entry `json:"entries"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns source name
+func (s *Source) Name() string {
+	return "CT logs"
+}
+
+// NeedsToken returns true if source can't be used without API token
+func (s *Source) NeedsToken() bool {
+	return false
+}
+
+// Find tries to find subdomains using CT logs from log list
+func (s *Source) Find(ctx context.Context, domain string) (api.Subdomains, error) {
+	logs, err := ReadLogList(s.LogList)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	var subdomains api.Subdomains
+
+	index := map[string]*api.Subdomain{}
+
+	for _, log := range logs {
+		err = s.scanLog(ctx, log, domain, index, &subdomains)
+
+		if err != nil {
+			errs = append(errs, err)
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if len(errs) == 0 {
+		return subdomains, nil
+	}
+
+	err = errors.Join(errs...)
+
+	// Results from other logs are still returned, so they are just incomplete
+	if len(errs) < len(logs) && ctx.Err() == nil {
+		return subdomains, fmt.Errorf(
+			"%d of %d CT logs failed: %w (%w)", len(errs), len(logs), api.ErrTruncated, err,
+		)
+	}
+
+	return subdomains, err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ReadLogList reads log list file and returns all not rejected logs
+func ReadLogList(file string) ([]*Log, error) {
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read log list: %w", err)
+	}
+
+	list := &logList{}
+	err = json.Unmarshal(data, list)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode log list: %w", err)
+	}
+
+	var result []*Log
+
+	for _, op := range list.Operators {
+		for _, log := range op.Logs {
+			if log.URL == "" || (log.State != nil && log.State.Rejected != nil) {
+				continue
+			}
+
+			result = append(result, log)
+		}
+	}
+
+	if len(result) == 0 {
+		return nil, fmt.Errorf("Log list %s doesn't contain any usable logs", file)
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// GetSTH fetches signed tree head from log
+func (l *Log) GetSTH(ctx context.Context) (int64, error) {
+	resp, err := req.Request{
+		URL:         l.endpoint("get-sth"),
+		Accept:      req.CONTENT_TYPE_JSON,
+		Timeout:     api.RequestTimeout(ctx),
+		AutoDiscard: true,
+	}.Get()
+
+	if err != nil {
+		return 0, fmt.Errorf("Can't send request to %s: %w", l.URL, err)
+	}
+
+	if resp.StatusCode > 299 {
+		return 0, api.StatusError(l.URL, resp.StatusCode)
+	}
+
+	head := &sth{}
+	err = resp.JSON(head)
+
+	if err != nil {
+		return 0, fmt.Errorf("Can't decode STH from %s: %w", l.URL, err)
+	}
+
+	return head.TreeSize, nil
+}
+
+// GetEntries fetches entries with given indexes (inclusive) from log. Log can
+// return less entries than requested.
+func (l *Log) GetEntries(ctx context.Context, start, end int64) ([]*Entry, error) {
+	resp, err := req.Request{
+		URL:         l.endpoint("get-entries"),
+		Query:       req.Query{"start": start, "end": end},
+		Accept:      req.CONTENT_TYPE_JSON,
+		Timeout:     api.RequestTimeout(ctx),
+		AutoDiscard: true,
+	}.Get()
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't send request to %s: %w", l.URL, err)
+	}
+
+	if resp.StatusCode > 299 {
+		return nil, api.StatusError(l.URL, resp.StatusCode)
+	}
+
+	data := &entries{}
+	err = resp.JSON(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode entries from %s: %w", l.URL, err)
+	}
+
+	return data.Entries, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// endpoint returns URL of API method
+func (l *Log) endpoint(method string) string {
+	url := l.URL
+
+	if !strings.Contains(url, "://") {
+		url = "https://" + url
+	}
+
+	return strings.TrimRight(url, "/") + "/ct/v1/" + method
+}
+
+// scanLog fetches entries from log and extracts subdomains of given domain
+func (s *Source) scanLog(ctx context.Context, log *Log, domain string, index map[string]*api.Subdomain, subdomains *api.Subdomains) error {
+	treeSize, err := log.GetSTH(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	start, end := s.getRange(treeSize)
+
+	for start <= end {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		entries, err := log.GetEntries(ctx, start, min(start+BATCH_SIZE-1, end))
+
+		if err != nil {
+			return err
+		}
+
+		if len(entries) == 0 {
+			break
+		}
+
+		for _, e := range entries {
+			leaf, err := parseLeaf(e.LeafInput)
+
+			if err != nil {
+				continue
+			}
+
+			for _, name := range leaf.Names {
+				if !api.IsValidName(name, domain) {
+					continue
+				}
+
+				subdomain := index[name]
+
+				if subdomain == nil {
+					subdomain = &api.Subdomain{Name: name}
+					index[name] = subdomain
+					*subdomains = append(*subdomains, subdomain)
+				}
+
+				subdomain.Seen(leaf.Timestamp)
+			}
+		}
+
+		start += int64(len(entries))
+	}
+
+	return nil
+}
+
+// getRange returns range of entries indexes for log with given size
+func (s *Source) getRange(treeSize int64) (int64, int64) {
+	start, end := s.Start, s.End
+	maxEntries := s.MaxEntries
+
+	if maxEntries <= 0 {
+		maxEntries = DEFAULT_MAX_ENTRIES
+	}
+
+	if end <= 0 || end >= treeSize {
+		end = treeSize - 1
+	}
+
+	switch {
+	case s.Start <= 0 && s.End <= 0:
+		start = end - maxEntries + 1
+	case s.End <= 0:
+		end = min(end, start+maxEntries-1)
+	}
+
+	return max(start, 0), end
+}
@@ -0,0 +1,215 @@
+package ctlog
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Log entry types
+const (
+	ENTRY_X509    = 0
+	ENTRY_PRECERT = 1
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Leaf contains info from Merkle tree leaf
+type Leaf struct {
+	Timestamp time.Time
+	EntryType uint16
+	Names     []string // DNS names from certificate subject CN and SAN extension
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// certificate is X.509 certificate
+type certificate struct {
+	TBSCertificate     asn1.RawValue
+	SignatureAlgorithm asn1.RawValue
+	SignatureValue     asn1.BitString
+}
+
+// tbsCertificate is X.509 TBSCertificate
+type tbsCertificate struct {
+	Version            int `asn1:"optional,explicit,default:0,tag:0"`
+	SerialNumber       asn1.RawValue
+	SignatureAlgorithm asn1.RawValue
+	Issuer             asn1.RawValue
+	Validity           asn1.RawValue
+	Subject            asn1.RawValue
+	PublicKey          asn1.RawValue
+	IssuerUniqueID     asn1.BitString   `asn1:"optional,tag:1"`
+	SubjectUniqueID    asn1.BitString   `asn1:"optional,tag:2"`
+	Extensions         []pkix.Extension `asn1:"optional,explicit,tag:3"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// oidSAN is OID of subject alternative name extension
+var oidSAN = asn1.ObjectIdentifier{2, 5, 29, 17}
+
+// errShortLeaf is returned if leaf data is too short
+var errShortLeaf = errors.New("Leaf data is too short")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseLeaf parses MerkleTreeLeaf structure (RFC 6962, section 3.4)
+func parseLeaf(data []byte) (*Leaf, error) {
+	// version (1) + leaf_type (1) + timestamp (8) + entry_type (2)
+	if len(data) < 12 {
+		return nil, errShortLeaf
+	}
+
+	if data[0] != 0 || data[1] != 0 {
+		return nil, fmt.Errorf("Unsupported leaf version (%d) or type (%d)", data[0], data[1])
+	}
+
+	leaf := &Leaf{
+		Timestamp: time.UnixMilli(int64(binary.BigEndian.Uint64(data[2:10]))).UTC(),
+		EntryType: binary.BigEndian.Uint16(data[10:12]),
+	}
+
+	data = data[12:]
+
+	var err error
+	var tbs []byte
+
+	switch leaf.EntryType {
+	case ENTRY_X509:
+		cert, err := readOpaque24(data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		tbs, err = extractTBS(cert)
+
+		if err != nil {
+			return nil, err
+		}
+
+	case ENTRY_PRECERT:
+		// issuer_key_hash (32)
+		if len(data) < 32 {
+			return nil, errShortLeaf
+		}
+
+		tbs, err = readOpaque24(data[32:])
+
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("Unsupported entry type %d", leaf.EntryType)
+	}
+
+	leaf.Names, err = extractNames(tbs)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return leaf, nil
+}
+
+// readOpaque24 reads opaque data with 24-bit length prefix
+func readOpaque24(data []byte) ([]byte, error) {
+	if len(data) < 3 {
+		return nil, errShortLeaf
+	}
+
+	size := int(data[0])<<16 | int(data[1])<<8 | int(data[2])
+
+	if len(data) < 3+size {
+		return nil, errShortLeaf
+	}
+
+	return data[3 : 3+size], nil
+}
+
+// extractTBS extracts TBSCertificate from DER encoded certificate
+func extractTBS(data []byte) ([]byte, error) {
+	cert := &certificate{}
+	_, err := asn1.Unmarshal(data, cert)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't parse certificate: %w", err)
+	}
+
+	return cert.TBSCertificate.FullBytes, nil
+}
+
+// extractNames extracts DNS names from DER encoded TBSCertificate
+func extractNames(data []byte) ([]string, error) {
+	tbs := &tbsCertificate{}
+	_, err := asn1.Unmarshal(data, tbs)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't parse TBSCertificate: %w", err)
+	}
+
+	var result []string
+
+	var subject pkix.RDNSequence
+	_, err = asn1.Unmarshal(tbs.Subject.FullBytes, &subject)
+
+	if err == nil {
+		var name pkix.Name
+		name.FillFromRDNSequence(&subject)
+
+		if strings.Contains(name.CommonName, ".") {
+			result = append(result, name.CommonName)
+		}
+	}
+
+	for _, ext := range tbs.Extensions {
+		if !ext.Id.Equal(oidSAN) {
+			continue
+		}
+
+		var seq asn1.RawValue
+		_, err = asn1.Unmarshal(ext.Value, &seq)
+
+		if err != nil {
+			continue
+		}
+
+		for rest := seq.Bytes; len(rest) > 0; {
+			var gn asn1.RawValue
+			rest, err = asn1.Unmarshal(rest, &gn)
+
+			if err != nil {
+				break
+			}
+
+			// dNSName [2] IA5String
+			if gn.Class == asn1.ClassContextSpecific && gn.Tag == 2 {
+				result = append(result, string(gn.Bytes))
+			}
+		}
+	}
+
+	for i, name := range result {
+		result[i] = strings.TrimRight(strings.ToLower(name), ".")
+	}
+
+	slices.Sort(result)
+
+	return slices.Compact(result), nil
+}
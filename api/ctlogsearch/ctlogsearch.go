@@ -8,15 +8,23 @@ package ctlogsearch
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/essentialkaos/ek/v13/req"
+
+	"github.com/essentialkaos/subdy/api"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// Source is CTLogSearch (CIDRE) API source
+type Source struct{}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
 // cert contains cert info
 type cert struct {
 	IssuedName string `json:"issuedname"`
@@ -29,12 +37,27 @@ type search struct {
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// Name returns source name
+func (s *Source) Name() string {
+	return "CTLogSearch"
+}
+
+// NeedsToken returns true if source can't be used without API token
+func (s *Source) NeedsToken() bool {
+	return false
+}
+
 // Find tries to find subdomains using CTLogSearch API
-func Find(domain string) ([]string, error) {
+func (s *Source) Find(ctx context.Context, domain string) (api.Subdomains, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	resp, err := req.Request{
 		URL:         "https://ctlogsearch.com/api/v1/search/domain/valid/" + domain,
 		Query:       req.Query{"_": time.Now().Unix()},
 		Accept:      req.CONTENT_TYPE_JSON,
+		Timeout:     api.RequestTimeout(ctx),
 		AutoDiscard: true,
 	}.Get()
 
@@ -43,7 +66,7 @@ func Find(domain string) ([]string, error) {
 	}
 
 	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("CTLogSearch API returned non-ok status code %d", resp.StatusCode)
+		return nil, api.StatusError("CTLogSearch", resp.StatusCode)
 	}
 
 	certs := &search{}
@@ -53,7 +76,7 @@ func Find(domain string) ([]string, error) {
 		return nil, fmt.Errorf("Can't decode CTLogSearch API response: %w", err)
 	}
 
-	var subdomains []string
+	var subdomains api.Subdomains
 
 	for _, cert := range certs.Data {
 		if strings.HasPrefix(cert.IssuedName, "*") ||
@@ -61,7 +84,7 @@ func Find(domain string) ([]string, error) {
 			continue
 		}
 
-		subdomains = append(subdomains, cert.IssuedName)
+		subdomains = append(subdomains, &api.Subdomain{Name: cert.IssuedName})
 	}
 
 	return subdomains, nil
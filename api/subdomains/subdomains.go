@@ -8,25 +8,50 @@ package subdomains
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/essentialkaos/ek/v13/req"
+
+	"github.com/essentialkaos/subdy/api"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// Source is subdomain.center API source
+type Source struct {
+	AuthCode string // Authentication code for beta API (optional)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Name returns source name
+func (s *Source) Name() string {
+	return "subdomain.center"
+}
+
+// NeedsToken returns true if source can't be used without API token
+func (s *Source) NeedsToken() bool {
+	return false
+}
+
 // Find tries to find subdomains using subdomain.center API
-func Find(domain, authCode string) ([]string, error) {
+func (s *Source) Find(ctx context.Context, domain string) (api.Subdomains, error) {
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
 	r := req.Request{
 		URL:         "https://api.subdomain.center",
 		Query:       req.Query{"domain": domain},
 		Accept:      req.CONTENT_TYPE_JSON,
+		Timeout:     api.RequestTimeout(ctx),
 		AutoDiscard: true,
 	}
 
-	if authCode != "" {
+	if s.AuthCode != "" {
 		r.URL = "https://api.subdomain.center/beta/"
-		r.Query.SetIf(authCode != "", "auth", authCode)
+		r.Query.SetIf(s.AuthCode != "", "auth", s.AuthCode)
 	}
 
 	resp, err := r.Get()
@@ -36,16 +61,22 @@ func Find(domain, authCode string) ([]string, error) {
 	}
 
 	if resp.StatusCode > 299 {
-		return nil, fmt.Errorf("subdomain.center API returned non-ok status code %d", resp.StatusCode)
+		return nil, api.StatusError("subdomain.center", resp.StatusCode)
 	}
 
-	subdomains := make([]string, 0)
-	err = resp.JSON(&subdomains)
+	names := make([]string, 0)
+	err = resp.JSON(&names)
 
 	if err != nil {
 		return nil, fmt.Errorf("Can't decode API response: %w", err)
 	}
 
+	var subdomains api.Subdomains
+
+	for _, name := range names {
+		subdomains = append(subdomains, &api.Subdomain{Name: name})
+	}
+
 	return subdomains, nil
 }
 
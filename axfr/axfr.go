@@ -0,0 +1,158 @@
+package axfr
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/subdy/dns"
+	"github.com/essentialkaos/subdy/workers"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_TIMEOUT is default timeout for zone transfer
+const DEFAULT_TIMEOUT = 10 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Checker attempts zone transfers from authoritative name servers
+type Checker struct {
+	Resolver dns.Resolver  // Resolver for NS and name servers addresses lookup
+	Timeout  time.Duration // Zone transfer timeout (default: 10s)
+	Workers  int           // Number of concurrent workers for zones lookup (default: 16)
+}
+
+// Transfer contains info about successful zone transfer
+type Transfer struct {
+	Zone    string      // Transferred zone
+	Server  string      // Name server which allowed transfer
+	Addr    string      // Name server address
+	Records dns.Records // Zone records
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Zones returns names from given list which are delegated zones (have own NS
+// records)
+func (c *Checker) Zones(names []string) []string {
+	isZone := make([]bool, len(names))
+
+	workers.Run(
+		len(names), c.Workers,
+		func(index int) {
+			isZone[index] = len(dns.LookupNS(c.Resolver, names[index])) != 0
+		},
+		nil,
+	)
+
+	var result []string
+
+	for index, name := range names {
+		if isZone[index] {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
+// Check attempts zone transfer for given zone from all its name servers and
+// returns successful transfers
+func (c *Checker) Check(zone string) []*Transfer {
+	var result []*Transfer
+
+	zone = dns.Normalize(zone)
+
+	for _, server := range dns.LookupNS(c.Resolver, zone) {
+		transfer := c.transfer(zone, server)
+
+		if transfer != nil {
+			result = append(result, transfer)
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Names returns names of all records from transferred zone
+func (t *Transfer) Names() []string {
+	var result []string
+
+	seen := map[string]bool{}
+
+	for _, r := range t.Records {
+		name := dns.Normalize(r.Name)
+
+		if seen[name] || name == t.Zone || strings.HasPrefix(name, "*.") ||
+			!strings.HasSuffix(name, "."+t.Zone) {
+			continue
+		}
+
+		seen[name] = true
+		result = append(result, name)
+	}
+
+	return result
+}
+
+// Delegations returns child zones delegated from transferred zone
+func (t *Transfer) Delegations() []string {
+	var result []string
+
+	for _, r := range t.Records {
+		name := dns.Normalize(r.Name)
+
+		if r.Type != dns.TYPE_NS || name == t.Zone || slices.Contains(result, name) {
+			continue
+		}
+
+		result = append(result, name)
+	}
+
+	return result
+}
+
+// String returns string representation of transfer
+func (t *Transfer) String() string {
+	return fmt.Sprintf(
+		"%s from %s (%s): %d records",
+		t.Zone, t.Server, t.Addr, len(t.Records),
+	)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// transfer attempts zone transfer from every address of given server
+func (c *Checker) transfer(zone, server string) *Transfer {
+	for _, ip := range dns.LookupAddrs(c.Resolver, server) {
+		addr := net.JoinHostPort(ip, "53")
+		records, err := dns.Transfer(addr, zone, c.getTimeout())
+
+		if err == nil {
+			return &Transfer{Zone: zone, Server: server, Addr: addr, Records: records}
+		}
+	}
+
+	return nil
+}
+
+// getTimeout returns zone transfer timeout
+func (c *Checker) getTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DEFAULT_TIMEOUT
+	}
+
+	return c.Timeout
+}
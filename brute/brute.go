@@ -0,0 +1,150 @@
+package brute
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/subdy/dns"
+	"github.com/essentialkaos/subdy/wildcard"
+	"github.com/essentialkaos/subdy/workers"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config contains brute-force configuration
+type Config struct {
+	Workers  int                   // Number of concurrent workers (default: 16)
+	Rate     int                   // Maximum number of queries per second (0 = no limit)
+	Wildcard *wildcard.Detector    // Wildcard detector for filtering false positives (optional)
+	Progress func(done, total int) // Progress handler (optional)
+}
+
+// Hit contains info about resolved name
+type Hit struct {
+	Name   string
+	Answer *dns.Answer
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+//go:embed wordlist.txt
+var defaultWordlist []byte
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DefaultWordlist returns built-in wordlist
+func DefaultWordlist() []string {
+	words, _ := parseWordlist(bytes.NewReader(defaultWordlist))
+	return words
+}
+
+// ReadWordlist reads wordlist from file
+func ReadWordlist(file string) ([]string, error) {
+	fd, err := os.Open(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't open wordlist: %w", err)
+	}
+
+	defer fd.Close()
+
+	words, err := parseWordlist(fd)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read wordlist: %w", err)
+	}
+
+	if len(words) == 0 {
+		return nil, fmt.Errorf("Wordlist %s is empty", file)
+	}
+
+	return words, nil
+}
+
+// Candidates generates candidate names using given words and domain
+func Candidates(words []string, domain string) []string {
+	var result []string
+
+	for _, word := range words {
+		result = append(result, word+"."+domain)
+	}
+
+	return result
+}
+
+// Resolve resolves given names using bounded pool of workers and returns names
+// which exist. Order of hits is the same as order of names.
+func Resolve(resolver dns.Resolver, names []string, config Config) []*Hit {
+	resolver = dns.Limit(resolver, config.Rate)
+	hits := make([]*Hit, len(names))
+
+	workers.Run(
+		len(names), config.Workers,
+		func(index int) {
+			hits[index] = check(resolver, names[index], config.Wildcard)
+		},
+		config.Progress,
+	)
+
+	return slices.DeleteFunc(hits, func(h *Hit) bool { return h == nil })
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// check resolves name and returns hit if name exists and doesn't match wildcard
+func check(resolver dns.Resolver, name string, wcDetector *wildcard.Detector) *Hit {
+	answer, err := resolver.Resolve(name, dns.TYPE_A)
+
+	if err != nil || answer.Status != dns.STATUS_NOERROR {
+		return nil
+	}
+
+	// Name exists, but may have only IPv6 addresses
+	if answer.IsEmpty() {
+		answer, err = resolver.Resolve(name, dns.TYPE_AAAA)
+
+		if err != nil || answer.IsEmpty() {
+			return nil
+		}
+	}
+
+	if wcDetector != nil && wcDetector.Match(name, answer) != nil {
+		return nil
+	}
+
+	return &Hit{Name: name, Answer: answer}
+}
+
+// parseWordlist parses wordlist skipping empty lines and comments
+func parseWordlist(r io.Reader) ([]string, error) {
+	var result []string
+
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		word := strings.Trim(strings.ToLower(strings.TrimSpace(scanner.Text())), ".")
+
+		if word == "" || strings.HasPrefix(word, "#") || seen[word] {
+			continue
+		}
+
+		seen[word] = true
+		result = append(result, word)
+	}
+
+	return result, scanner.Err()
+}
@@ -8,9 +8,16 @@ package app
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"net"
 	"os"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,11 +37,23 @@ import (
 	"github.com/essentialkaos/ek/v13/usage/man"
 	"github.com/essentialkaos/ek/v13/usage/update"
 
+	"github.com/essentialkaos/subdy/api"
 	"github.com/essentialkaos/subdy/api/certspotter"
+	"github.com/essentialkaos/subdy/api/crtsh"
+	"github.com/essentialkaos/subdy/api/ctlog"
 	"github.com/essentialkaos/subdy/api/ctlogsearch"
 	"github.com/essentialkaos/subdy/api/subdomains"
+	"github.com/essentialkaos/subdy/axfr"
+	"github.com/essentialkaos/subdy/brute"
 	"github.com/essentialkaos/subdy/dns"
+	"github.com/essentialkaos/subdy/dnssec"
+	"github.com/essentialkaos/subdy/permute"
 	"github.com/essentialkaos/subdy/probe"
+	"github.com/essentialkaos/subdy/reverse"
+	"github.com/essentialkaos/subdy/takeover"
+	"github.com/essentialkaos/subdy/wildcard"
+	"github.com/essentialkaos/subdy/workers"
+	"github.com/essentialkaos/subdy/zonewalk"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -53,10 +72,39 @@ const (
 	OPT_IP       = "I:ip"
 	OPT_DNS      = "D:dns"
 	OPT_PROBE    = "P:probe"
+	OPT_RECORDS  = "R:records"
+	OPT_NO_WC    = "W:no-wildcards"
+	OPT_BRUTE    = "B:brute"
+	OPT_PERMUTE  = "M:permute"
+	OPT_RECURSE  = "r:recursive"
+	OPT_AXFR     = "A:axfr"
+	OPT_PTR      = "ptr"
+	OPT_SWEEP    = "sweep"
+	OPT_TAKEOVER = "takeover"
+	OPT_DNSSEC   = "dnssec"
+	OPT_CACHE    = "cache"
+	OPT_THREADS  = "t:threads"
+	OPT_QPS      = "qps"
+	OPT_RESOLVED = "only-resolved"
+	OPT_DEAD     = "only-dead"
+	OPT_TIMEOUT  = "T:timeout"
+	OPT_DEADLINE = "deadline"
+	OPT_STRICT   = "strict"
 	OPT_NO_COLOR = "nc:no-color"
 	OPT_HELP     = "h:help"
 	OPT_VER      = "v:version"
 
+	OPT_CERTSPOTTER_PAGES   = "certspotter-pages"
+	OPT_CERTSPOTTER_HISTORY = "certspotter-history"
+	OPT_CT_LOGS             = "ct-logs"
+	OPT_CT_RANGE            = "ct-range"
+	OPT_BRUTE_RATE          = "brute-rate"
+	OPT_ZONE_WALK           = "zone-walk"
+	OPT_SWEEP_PREFIX        = "sweep-prefix"
+	OPT_TAKEOVER_DB         = "takeover-db"
+	OPT_PERMUTE_LIMIT       = "permute-limit"
+	OPT_DNSSEC_VALIDATE     = "dnssec-validate"
+
 	OPT_VERB_VER     = "vv:verbose-version"
 	OPT_COMPLETION   = "completion"
 	OPT_GENERATE_MAN = "generate-man"
@@ -64,6 +112,24 @@ const (
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
+// Exit codes
+const (
+	EC_OK             = 0
+	EC_ERROR          = 1
+	EC_SOURCES_FAILED = 2 // Some sources failed, results may be incomplete
+)
+
+// Subdomains resolution statuses
+const (
+	RESOLVE_OK         = "resolved"
+	RESOLVE_NXDOMAIN   = "NXDOMAIN"
+	RESOLVE_SERVFAIL   = "SERVFAIL"
+	RESOLVE_NO_ADDRESS = "no address"
+	RESOLVE_ERROR      = "error"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
 const (
 	// ENV_CERT_SPOTTER is environment variable name with CertSpotter API token
 	ENV_CERT_SPOTTER = "CT_TOKEN"
@@ -77,9 +143,19 @@ const (
 
 // subdomain contains subdomain info
 type subdomain struct {
-	name     string
-	ip       *dns.Answer
-	services []string
+	name      string
+	ip        *dns.Answer
+	services  []string
+	records   dns.Records
+	wildcard  *wildcard.Wildcard
+	takeover  *takeover.Candidate
+	dnssec    string
+	status    string
+	statusErr error
+	sources   []string
+	issuers   []string
+	firstSeen time.Time
+	lastSeen  time.Time
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -89,10 +165,39 @@ var optMap = options.Map{
 	OPT_IP:       {Type: options.BOOL},
 	OPT_DNS:      {Type: options.STRING, Value: "cloudflare"},
 	OPT_PROBE:    {Type: options.BOOL},
+	OPT_RECORDS:  {},
+	OPT_NO_WC:    {Type: options.BOOL},
+	OPT_BRUTE:    {},
+	OPT_PERMUTE:  {Type: options.BOOL},
+	OPT_RECURSE:  {Type: options.INT, Min: 1, Max: 10},
+	OPT_AXFR:     {Type: options.BOOL},
+	OPT_PTR:      {Type: options.BOOL},
+	OPT_SWEEP:    {Type: options.BOOL},
+	OPT_TAKEOVER: {Type: options.BOOL},
+	OPT_DNSSEC:   {Type: options.BOOL},
+	OPT_CACHE:    {},
+	OPT_THREADS:  {Type: options.INT, Value: 16, Min: 1, Max: 512},
+	OPT_QPS:      {Type: options.INT, Value: 200, Min: 0, Max: 100000},
+	OPT_RESOLVED: {Type: options.BOOL},
+	OPT_DEAD:     {Type: options.BOOL},
+	OPT_TIMEOUT:  {Type: options.INT, Value: 30, Min: 1, Max: 3600},
+	OPT_DEADLINE: {Type: options.INT, Value: 120, Min: 1, Max: 86400},
+	OPT_STRICT:   {Type: options.BOOL},
 	OPT_NO_COLOR: {Type: options.BOOL},
 	OPT_HELP:     {Type: options.BOOL},
 	OPT_VER:      {Type: options.MIXED},
 
+	OPT_CERTSPOTTER_PAGES:   {Type: options.INT, Value: 10, Min: 0, Max: 100000},
+	OPT_CERTSPOTTER_HISTORY: {Type: options.BOOL},
+	OPT_CT_LOGS:             {},
+	OPT_CT_RANGE:            {},
+	OPT_BRUTE_RATE:          {Type: options.INT, Value: 100, Min: 0, Max: 100000},
+	OPT_ZONE_WALK:           {Type: options.BOOL},
+	OPT_SWEEP_PREFIX:        {Type: options.INT, Value: 24, Min: 16, Max: 32},
+	OPT_TAKEOVER_DB:         {},
+	OPT_PERMUTE_LIMIT:       {Type: options.INT, Value: permute.DEFAULT_LIMIT, Min: 1, Max: 1000000},
+	OPT_DNSSEC_VALIDATE:     {Type: options.BOOL},
+
 	OPT_VERB_VER:     {Type: options.BOOL},
 	OPT_COMPLETION:   {},
 	OPT_GENERATE_MAN: {Type: options.BOOL},
@@ -106,6 +211,15 @@ var dohProviders = map[string]string{
 	"quad9":      dns.QUAD9,
 }
 
+// dohWireURLs contains RFC 8484 endpoints of providers which JSON API uses
+// different URL
+var dohWireURLs = map[string]string{
+	dns.GOOGLE: "dns.google/dns-query",
+}
+
+// errSourcesFailed is returned if some sources failed but we have results
+var errSourcesFailed = errors.New("Some sources failed")
+
 // useRawOutput is raw output flag (for cli command)
 var useRawOutput = false
 
@@ -158,9 +272,12 @@ func Run(gitRev string, gomod []byte) {
 
 	err = process(args)
 
-	if err != nil {
+	switch {
+	case errors.Is(err, errSourcesFailed):
+		os.Exit(EC_SOURCES_FAILED)
+	case err != nil:
 		terminal.Error(err)
-		os.Exit(1)
+		os.Exit(EC_ERROR)
 	}
 }
 
@@ -197,11 +314,62 @@ func validateOptionsAndArgs(args options.Arguments) error {
 		return fmt.Errorf("%q is not valid domain", domain)
 	}
 
+	if options.Has(OPT_CT_RANGE) {
+		_, _, err := parseRange(options.GetS(OPT_CT_RANGE))
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Has(OPT_BRUTE) {
+		_, err := getWordlist()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Has(OPT_TAKEOVER_DB) {
+		_, err := getFingerprints()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.Has(OPT_RECORDS) {
+		_, err := getRecordTypes()
+
+		if err != nil {
+			return err
+		}
+	}
+
 	if options.Has(OPT_DNS) {
-		dns := options.GetS(OPT_DNS)
+		for _, dns := range getProviders() {
+			if !strings.Contains(dns, ".") && !strings.Contains(dns, "://") && dohProviders[dns] == "" {
+				return fmt.Errorf("Unknown DNS provider %q", dns)
+			}
+		}
+
+		_, err := getResolver()
+
+		if err != nil {
+			return err
+		}
+	}
+
+	if options.GetB(OPT_RESOLVED) && options.GetB(OPT_DEAD) {
+		return fmt.Errorf("Options --%s and --%s can't be used together", OPT_RESOLVED, OPT_DEAD)
+	}
 
-		if !strings.Contains(dns, ".") && dohProviders[dns] == "" {
-			return fmt.Errorf("Unknown DNS-over-HTTPS provider %q", dns)
+	if options.GetB(OPT_DNSSEC_VALIDATE) {
+		resolver, _ := getResolver()
+		_, err := getExchanger(resolver)
+
+		if err != nil {
+			return err
 		}
 	}
 
@@ -211,254 +379,1561 @@ func validateOptionsAndArgs(args options.Arguments) error {
 // process starts arguments processing
 func process(args options.Arguments) error {
 	domain := args.Get(0).ToLower().String()
-	subdomains := searchSubdomains(domain)
+
+	registerSources()
+
+	// Deadline is shared by search of domain and all its sub-zones
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Duration(options.GetI(OPT_DEADLINE))*time.Second,
+	)
+
+	defer cancel()
+
+	index := map[string]*subdomain{}
+	results := searchSubdomains(ctx, domain, index)
+	hasFailed := slices.ContainsFunc(results, isSourceFailed)
+
+	err := checkStrictMode(results)
+
+	if err != nil {
+		return err
+	}
+
+	upstream, _ := getResolver()
+	resolver := dns.Cache(upstream)
+
+	defer closeResolver(upstream)
+	wcDetector := &wildcard.Detector{Resolver: resolver}
+
+	if options.Has(OPT_CACHE) {
+		err := resolver.Load(options.GetS(OPT_CACHE))
+
+		if err != nil {
+			terminal.Warn(err)
+		}
+
+		defer saveCache(resolver)
+	}
+
+	if options.Has(OPT_BRUTE) {
+		bruteforceSubdomains(domain, index, resolver, wcDetector)
+	}
+
+	if options.Has(OPT_RECURSE) {
+		results = append(results, searchSubZones(ctx, domain, index, resolver, wcDetector)...)
+		hasFailed = slices.ContainsFunc(results, isSourceFailed)
+
+		err = checkStrictMode(results)
+
+		if err != nil {
+			return err
+		}
+	}
+
+	var transfers []*axfr.Transfer
+
+	if options.GetB(OPT_AXFR) {
+		transfers = transferZones(domain, index, resolver)
+	}
+
+	var walkResult *zonewalk.Result
+	var walkErr error
+
+	if options.GetB(OPT_ZONE_WALK) {
+		walkResult, walkErr = walkZone(domain, index, resolver)
+	}
+
+	if options.GetB(OPT_PERMUTE) {
+		permuteSubdomains(domain, index, resolver, wcDetector)
+	}
+
+	subdomains := sortSubdomains(index)
 
 	if len(subdomains) == 0 {
+		printSourcesStatus(results)
 		terminal.Warn("There are no subdomains for this domain")
+
+		if hasFailed {
+			return errSourcesFailed
+		}
+
 		return nil
 	}
 
-	subdomainsInfo := processSubdomains(subdomains)
+	subdomainsInfo := processSubdomains(subdomains, resolver, wcDetector)
+
+	if options.GetB(OPT_PTR) {
+		subdomainsInfo = addPTRTargets(domain, index, subdomainsInfo, resolver, wcDetector)
+	}
+
+	if options.GetB(OPT_SWEEP) {
+		subdomainsInfo = sweepNetworks(domain, index, subdomainsInfo, resolver, wcDetector)
+	}
+
+	if options.GetB(OPT_TAKEOVER) {
+		checkTakeovers(subdomainsInfo, resolver)
+	}
+
+	var validator *dnssec.Validator
+
+	if options.GetB(OPT_DNSSEC) || options.GetB(OPT_DNSSEC_VALIDATE) {
+		validator = checkDNSSEC(subdomainsInfo, resolver)
+	}
 
 	if !useRawOutput {
 		printSubdomainsInfo(subdomainsInfo)
+		printTransfers(transfers)
+		printZoneWalkResult(walkResult, walkErr)
+		printTakeovers(subdomainsInfo)
+		printDNSSECZones(validator)
+		printWildcards(wcDetector.Wildcards())
 	} else {
 		printRawSubdomainsInfo(subdomainsInfo)
+		printTransfers(transfers)
+		printTakeovers(subdomainsInfo)
+	}
+
+	printResolversHealth(upstream)
+	printCacheStats(resolver)
+	printSourcesStatus(results)
+
+	if hasFailed {
+		return errSourcesFailed
 	}
 
 	return nil
 }
 
-// searchSubdomains searches subdomains using various sources
-func searchSubdomains(domain string) []string {
-	var result []string
-
-	fmtc.If(!useRawOutput).TPrintf("{s-}Searching subdomains using subdomain.center…{!}")
+// registerSources registers all supported subdomains sources
+func registerSources() {
+	api.Register(&subdomains.Source{AuthCode: os.Getenv(ENV_SUBDOMAINS)})
+	api.Register(&ctlogsearch.Source{})
+	api.Register(&crtsh.Source{})
+	api.Register(&certspotter.Source{
+		Token:    os.Getenv(ENV_CERT_SPOTTER),
+		MaxPages: options.GetI(OPT_CERTSPOTTER_PAGES),
+		History:  options.GetB(OPT_CERTSPOTTER_HISTORY),
+	})
 
-	subdomains, err := subdomains.Find(domain, os.Getenv(ENV_SUBDOMAINS))
+	if options.Has(OPT_CT_LOGS) {
+		start, end, _ := parseRange(options.GetS(OPT_CT_RANGE))
 
-	if err == nil {
-		result = append(result, subdomains...)
+		api.Register(&ctlog.Source{
+			LogList: options.GetS(OPT_CT_LOGS),
+			Start:   start,
+			End:     end,
+		})
 	}
+}
 
-	fmtc.If(!useRawOutput).TPrintf("{s-}Searching subdomains using CTLogSearch (CIDRE)…{!}")
+// searchSubdomains searches subdomains using various sources and adds them to index
+func searchSubdomains(ctx context.Context, domain string, index map[string]*subdomain) []*api.Result {
+	var results []*api.Result
 
-	subdomains, err = ctlogsearch.Find(domain)
+	total, done := len(api.Sources()), 0
+	timeout := time.Duration(options.GetI(OPT_TIMEOUT)) * time.Second
 
-	if err == nil {
-		result = append(result, subdomains...)
-	}
+	fmtc.If(!useRawOutput).TPrintf(
+		"{s-}Searching subdomains of %s using %d sources…{!}", domain, total,
+	)
 
-	fmtc.If(!useRawOutput).TPrintf("{s-}Searching subdomains using CertSpotter…{!}")
+	for res := range api.Search(ctx, domain, timeout) {
+		done++
+		results = append(results, res)
 
-	subdomains, err = certspotter.Find(domain, os.Getenv(ENV_CERT_SPOTTER))
+		// Sources can return partial results with error
+		addSubdomains(index, res.Source.Name(), res.Subdomains)
 
-	if err == nil {
-		fmtc.If(!useRawOutput).TPrintf("")
+		fmtc.If(!useRawOutput).TPrintf(
+			"{s-}[%d/%d] Searching subdomains of %s… %s is done (found: %d){!}",
+			done, total, domain, res.Source.Name(), len(index),
+		)
 	}
 
-	result = append(result, subdomains...)
+	fmtc.If(!useRawOutput).TPrintf("")
 
-	return result
+	return results
 }
 
-// processSubdomains enriches subdomains info
-func processSubdomains(subdomains []string) []*subdomain {
-	var result []*subdomain
-
-	defer fmtc.If(!useRawOutput).TPrintf("")
-
-	resolver := getDoHResolver()
+// searchSubZones recursively searches subdomains of found intermediate zones
+// (e.g. eu.corp.example.com for app.eu.corp.example.com). Every zone is searched
+// only once. It returns results of sources which failed to search sub-zones.
+func searchSubZones(ctx context.Context, domain string, index map[string]*subdomain, resolver dns.Resolver, wcDetector *wildcard.Detector) []*api.Result {
+	var failed []*api.Result
 
-	sortutil.StringsNatural(subdomains)
-	subdomains = slices.CompactFunc(subdomains, strings.EqualFold)
+	searched := map[string]bool{domain: true}
 
-	for index, name := range subdomains {
-		name = strings.ToLower(name)
+	for range options.GetI(OPT_RECURSE) {
+		zones := findSubZones(domain, index, searched)
 
-		if options.GetB(OPT_IP) || options.GetB(OPT_PROBE) {
-			fmtc.If(!useRawOutput).TPrintf(
-				"{s-}[%d/%d] Resolving %s IP…{!}",
-				index, len(subdomains), name,
-			)
+		if len(zones) == 0 {
+			break
+		}
 
-			answer, err := resolver.Resolve(name)
+		for _, zone := range zones {
+			searched[zone] = true
 
-			if err != nil {
-				continue
+			for _, res := range searchSubdomains(ctx, zone, index) {
+				// Skipped sources are already reported by domain search
+				if res.Error != nil && res.Status() != api.STATUS_SKIPPED {
+					res.Error = fmt.Errorf("%s: %w", zone, res.Error)
+					failed = append(failed, res)
+				}
 			}
 
-			result = append(result, &subdomain{name: name, ip: answer})
-		} else {
-			result = append(result, &subdomain{name: name})
+			if options.Has(OPT_BRUTE) {
+				bruteforceSubdomains(zone, index, resolver, wcDetector)
+			}
 		}
 	}
 
-	if !useRawOutput && options.GetB(OPT_PROBE) {
-		for index, info := range result {
-			fmtc.TPrintf(
-				"{s-}[%d/%d] Probing %s…{!}",
-				index, len(result), info.name,
-			)
+	return failed
+}
 
-			info.services = probe.Probe(info.ip.IP())
-		}
+// checkStrictMode returns error if some sources failed and strict mode is enabled
+func checkStrictMode(results []*api.Result) error {
+	if !options.GetB(OPT_STRICT) || !slices.ContainsFunc(results, isSourceFailed) {
+		return nil
 	}
 
-	return result
+	printSourcesStatus(results)
+
+	return fmt.Errorf("Search failed: some sources returned errors")
 }
 
-// printSubdomainsInfo prints subdomains info
-func printSubdomainsInfo(subdomains []*subdomain) {
-	fmtc.NewLine()
+// findSubZones returns intermediate zones of found subdomains which weren't
+// searched yet
+func findSubZones(domain string, index map[string]*subdomain, searched map[string]bool) []string {
+	var result []string
 
-	for _, info := range subdomains {
-		if !info.ip.IsEmpty() {
-			fmtc.Printf(
-				" {s}•{!} %s {s-}(%s){!}",
-				info.name, info.ip.ToString(false),
-			)
-		} else {
-			fmtc.Printf(" {s}•{!} %s", info.name)
-		}
+	found := map[string]bool{}
 
-		if len(info.services) != 0 {
-			fmt.Print(" " + getColoredServicePorts(info.services))
-		}
+	for name := range index {
+		_, zone, _ := strings.Cut(name, ".")
 
-		fmtc.NewLine()
+		for strings.HasSuffix(zone, "."+domain) {
+			if !searched[zone] && !found[zone] {
+				found[zone] = true
+				result = append(result, zone)
+			}
+
+			_, zone, _ = strings.Cut(zone, ".")
+		}
 	}
 
-	fmtc.NewLine()
-}
+	sortutil.StringsNatural(result)
 
-// printRawSubdomainsInfo prints subdomains info for raw output
-func printRawSubdomainsInfo(subdomains []*subdomain) {
-	for _, info := range subdomains {
-		fmt.Println(info.name, info.ip.ToString(true))
-	}
+	return result
 }
 
-// getDoHResolver returns DoH resolver
-func getDoHResolver() *dns.Resolver {
-	resolverURL, ok := dohProviders[options.GetS(OPT_DNS)]
+// transferZones attempts zone transfers for domain and all found delegated zones
+// and adds names from successful transfers to index
+func transferZones(domain string, index map[string]*subdomain, resolver dns.Resolver) []*axfr.Transfer {
+	var result []*axfr.Transfer
 
-	if !ok {
-		resolverURL = strutil.Exclude(options.GetS(OPT_DNS), "https://")
+	checker := &axfr.Checker{Resolver: resolver, Workers: options.GetI(OPT_THREADS)}
+	names := []string{domain}
+
+	for _, info := range sortSubdomains(index) {
+		if api.IsValidName(info.name, domain) {
+			names = append(names, info.name)
+		}
 	}
 
-	return &dns.Resolver{resolverURL}
-}
+	fmtc.If(!useRawOutput).TPrintf(
+		"{s-}Looking for delegated zones among %d names…{!}", len(names),
+	)
 
-// getColoredServicePorts formats list of services
-func getColoredServicePorts(services []string) string {
-	return strutil.JoinFunc(services, " ", func(s string) string {
-		colorTag := "{#152}"
+	zones := checker.Zones(names)
+	checked := map[string]bool{}
 
-		switch s {
-		case "22", "23", "5800":
-			colorTag = "{#67}"
-		case "25", "110", "143", "220", "993", "995":
-			colorTag = "{#173}"
-		case "21", "115", "445", "636", "990", "3389":
-			colorTag = "{#140}"
-		case "80", "443", "3000", "8080", "8443", "9000":
-			colorTag = "{#151}"
-		case "53":
-			colorTag = "{#153}"
-		case "1434", "3306", "3690", "5432", "6379", "6432", "9042", "27017":
-			colorTag = "{#221}"
+	// Zones delegated in transferred zones are added to the queue
+	for i := 0; i < len(zones); i++ {
+		zone := zones[i]
+
+		if checked[zone] {
+			continue
 		}
 
-		return fmtc.Sprintf(colorTag+"[%s]{!}", s)
-	})
-}
+		checked[zone] = true
 
-// ////////////////////////////////////////////////////////////////////////////////// //
+		fmtc.If(!useRawOutput).TPrintf(
+			"{s-}[%d/%d] Trying zone transfer for %s…{!}", i+1, len(zones), zone,
+		)
 
-// checkAPIAvailability checks API availability
-func checkAPIAvailability() support.Check {
-	chk := support.Check{support.CHECK_ERROR, "subdomain.center API", ""}
+		for _, transfer := range checker.Check(zone) {
+			var found api.Subdomains
 
-	start := time.Now()
-	resp, err := req.Request{
-		URL:         "https://api.subdomain.center",
-		AutoDiscard: true,
-	}.Get()
-	dur := time.Since(start)
+			for _, name := range transfer.Names() {
+				found = append(found, &api.Subdomain{Name: name})
+			}
+
+			addSubdomains(index, "axfr", found)
+
+			// Transferred zone can contain NS records of names outside of domain
+			for _, delegation := range transfer.Delegations() {
+				if api.IsValidName(delegation, domain) {
+					zones = append(zones, delegation)
+				}
+			}
+
+			result = append(result, transfer)
+		}
+	}
+
+	fmtc.If(!useRawOutput).TPrintf("")
+
+	return result
+}
+
+// walkZone enumerates names of DNSSEC-signed zone using NSEC/NSEC3 records and
+// adds found names to index
+func walkZone(domain string, index map[string]*subdomain, resolver dns.Resolver) (*zonewalk.Result, error) {
+	fmtc.If(!useRawOutput).TPrintf("{s-}Walking zone %s…{!}", domain)
+	defer fmtc.If(!useRawOutput).TPrintf("")
+
+	exchanger, err := getZoneExchanger(domain, resolver)
 
 	if err != nil {
-		chk.Message = "Can't send request"
-		return chk
+		return nil, err
 	}
 
-	if resp.StatusCode != 200 {
-		chk.Message = fmt.Sprintf("API returned non-ok status code (%d)", resp.StatusCode)
-		return chk
+	walker := &zonewalk.Walker{Exchanger: exchanger}
+	result, err := walker.Walk(domain)
+
+	if result == nil {
+		return nil, err
 	}
 
-	chk.Status = support.CHECK_OK
+	if len(result.Hashes) != 0 {
+		words := brute.DefaultWordlist()
 
-	if dur < 500*time.Millisecond {
-		chk.Message = "accessible and healthy"
-	} else {
-		chk.Message = "accessible"
+		if options.Has(OPT_BRUTE) {
+			words, _ = getWordlist()
+		}
+
+		fmtc.If(!useRawOutput).TPrintf(
+			"{s-}Cracking %d NSEC3 hashes using %d words…{!}",
+			len(result.Hashes), len(words),
+		)
+
+		result.Crack(words)
 	}
 
-	return chk
+	var found api.Subdomains
+
+	for _, name := range result.Names {
+		found = append(found, &api.Subdomain{Name: name})
+	}
+
+	addSubdomains(index, "zonewalk", found)
+
+	return result, err
 }
 
-// printCompletion prints completion for given shell
-func printCompletion() int {
-	info := genUsage()
+// getZoneExchanger returns wire-format resolver for zone walking. Authoritative
+// servers are preferred, because recursive resolvers can strip NSEC records.
+func getZoneExchanger(zone string, resolver dns.Resolver) (dns.Exchanger, error) {
+	for _, server := range dns.LookupNS(resolver, zone) {
+		addrs := dns.LookupAddrs(resolver, server)
 
-	switch options.GetS(OPT_COMPLETION) {
-	case "bash":
-		fmt.Print(bash.Generate(info, APP))
-	case "fish":
-		fmt.Print(fish.Generate(info, APP))
-	case "zsh":
-		fmt.Print(zsh.Generate(info, optMap, APP))
-	default:
-		return 1
+		if len(addrs) != 0 {
+			return &dns.NetResolver{Addr: net.JoinHostPort(addrs[0], "53")}, nil
+		}
 	}
 
-	return 0
+	exchanger, err := getExchanger(resolver)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't find authoritative servers for %s", zone)
+	}
+
+	return exchanger, nil
 }
 
-// printMan prints man page
-func printMan() {
-	fmt.Println(man.Generate(genUsage(), genAbout("")))
+// bruteforceSubdomains resolves candidates generated using wordlist and adds found
+// subdomains to index
+func bruteforceSubdomains(domain string, index map[string]*subdomain, resolver dns.Resolver, wcDetector *wildcard.Detector) {
+	words, _ := getWordlist()
+	candidates := brute.Candidates(words, domain)
+
+	candidates = slices.DeleteFunc(candidates, func(name string) bool {
+		return index[name] != nil
+	})
+
+	resolveCandidates(
+		index, "bruteforce", candidates, resolver, wcDetector,
+		"Brute-forcing subdomains",
+	)
 }
 
-// genUsage generates usage info
-func genUsage() *usage.Info {
-	info := usage.NewInfo("", "domain")
+// permuteSubdomains resolves permutations of already found subdomains and adds
+// found subdomains to index
+func permuteSubdomains(domain string, index map[string]*subdomain, resolver dns.Resolver, wcDetector *wildcard.Detector) {
+	var names []string
 
-	info.AppNameColorTag = colorTagApp
+	for name := range index {
+		names = append(names, name)
+	}
 
-	info.AddOption(OPT_IP, "Resolve subdomains IP")
-	info.AddOption(OPT_DNS, "DoH JSON provider {s-}({_}cloudflare{!_}|google|quad9|custom-url){!}", "name-or-url")
-	info.AddOption(OPT_PROBE, "Probe subdomains for open ports")
-	info.AddOption(OPT_NO_COLOR, "Disable colors in output")
-	info.AddOption(OPT_HELP, "Show this help message")
-	info.AddOption(OPT_VER, "Show version")
+	sortutil.StringsNatural(names)
 
-	info.AddEnv(ENV_SUBDOMAINS, "Token for subdomain.center API")
-	info.AddEnv(ENV_CERT_SPOTTER, "Token for CertSpotter API")
+	candidates := permute.Generate(names, domain, permute.Config{
+		Limit: options.GetI(OPT_PERMUTE_LIMIT),
+	})
 
-	info.AddExample(
-		"go.dev", "Find all subdomains of go.dev",
+	resolveCandidates(
+		index, "permutation", candidates, resolver, wcDetector,
+		"Resolving permutations",
 	)
+}
 
-	info.AddExample(
-		"-I go.dev", "Find all subdomains of go.dev and resolve their IPs",
-	)
+// resolveCandidates resolves candidate names and adds existing names to index
+// as subdomains found by given source
+func resolveCandidates(index map[string]*subdomain, source string, candidates []string, resolver dns.Resolver, wcDetector *wildcard.Detector, message string) {
+	if len(candidates) == 0 {
+		return
+	}
+
+	hits := brute.Resolve(resolver, candidates, brute.Config{
+		Workers:  options.GetI(OPT_THREADS),
+		Rate:     options.GetI(OPT_BRUTE_RATE),
+		Wildcard: wcDetector,
+		Progress: func(done, total int) {
+			fmtc.If(!useRawOutput).TPrintf(
+				"{s-}[%d/%d] %s…{!}", done, total, message,
+			)
+		},
+	})
+
+	fmtc.If(!useRawOutput).TPrintf("")
+
+	var found api.Subdomains
+
+	for _, hit := range hits {
+		found = append(found, &api.Subdomain{Name: hit.Name})
+	}
+
+	addSubdomains(index, source, found)
+}
+
+// sortSubdomains returns subdomains from index sorted by name
+func sortSubdomains(index map[string]*subdomain) []*subdomain {
+	var names []string
+
+	for name := range index {
+		names = append(names, name)
+	}
+
+	sortutil.StringsNatural(names)
+
+	result := make([]*subdomain, 0, len(names))
+
+	for _, name := range names {
+		result = append(result, index[name])
+	}
+
+	return result
+}
+
+// addSubdomains adds subdomains found by source to index
+func addSubdomains(index map[string]*subdomain, source string, subdomains api.Subdomains) {
+	for _, sd := range subdomains {
+		name := strings.TrimRight(strings.ToLower(sd.Name), ".")
+
+		if name == "" {
+			continue
+		}
+
+		info := index[name]
+
+		if info == nil {
+			info = &subdomain{name: name}
+			index[name] = info
+		}
+
+		if !slices.Contains(info.sources, source) {
+			info.sources = append(info.sources, source)
+		}
+
+		for _, issuer := range sd.Issuers {
+			if !slices.Contains(info.issuers, issuer) {
+				info.issuers = append(info.issuers, issuer)
+			}
+		}
+
+		if !sd.FirstSeen.IsZero() && (info.firstSeen.IsZero() || sd.FirstSeen.Before(info.firstSeen)) {
+			info.firstSeen = sd.FirstSeen
+		}
+
+		if sd.LastSeen.After(info.lastSeen) {
+			info.lastSeen = sd.LastSeen
+		}
+	}
+}
+
+// processSubdomains enriches subdomains info using pool of workers
+func processSubdomains(subdomains []*subdomain, resolver dns.Resolver, wcDetector *wildcard.Detector) []*subdomain {
+	defer fmtc.If(!useRawOutput).TPrintf("")
+
+	recordTypes, _ := getRecordTypes()
+	isProcessed := make([]bool, len(subdomains))
+
+	workers.Run(
+		len(subdomains), options.GetI(OPT_THREADS),
+		func(index int) {
+			isProcessed[index] = processSubdomain(subdomains[index], resolver, wcDetector, recordTypes)
+		},
+		func(done, total int) {
+			fmtc.If(!useRawOutput).TPrintf(
+				"{s-}[%d/%d] Resolving subdomains…{!}", done, total,
+			)
+		},
+	)
+
+	var result []*subdomain
+
+	for index, info := range subdomains {
+		if isProcessed[index] {
+			result = append(result, info)
+		}
+	}
+
+	if !useRawOutput && options.GetB(OPT_PROBE) {
+		for index, info := range result {
+			fmtc.TPrintf(
+				"{s-}[%d/%d] Probing %s…{!}",
+				index, len(result), info.name,
+			)
+
+			info.services = probe.Probe(append(info.ip.IP(), info.ip.IPv6()...))
+		}
+	}
+
+	return result
+}
+
+// processSubdomain resolves subdomain addresses and queries its records. It
+// returns false if subdomain must be dropped.
+func processSubdomain(info *subdomain, resolver dns.Resolver, wcDetector *wildcard.Detector, recordTypes []int) bool {
+	if isAddressesRequired() {
+		answer, err := resolveAddresses(resolver, info.name)
+
+		info.ip = answer
+		info.status, info.statusErr = getResolveStatus(answer, err)
+
+		switch {
+		case options.GetB(OPT_RESOLVED) && info.status != RESOLVE_OK,
+			options.GetB(OPT_DEAD) && info.status != RESOLVE_NXDOMAIN:
+			return false
+		}
+
+		if info.status == RESOLVE_OK {
+			info.wildcard = wcDetector.Match(info.name, answer)
+		}
+
+		if info.wildcard != nil && options.GetB(OPT_NO_WC) {
+			return false
+		}
+	}
+
+	if len(recordTypes) != 0 {
+		info.records = queryRecords(resolver, info.name, recordTypes)
+	}
+
+	if options.GetB(OPT_PTR) && !info.ip.IsEmpty() {
+		for _, ip := range append(info.ip.IP(), info.ip.IPv6()...) {
+			info.records = append(info.records, reverse.Lookup(resolver, ip)...)
+		}
+	}
+
+	return true
+}
+
+// sweepNetworks performs PTR lookups for networks around resolved addresses and
+// returns subdomains info with subdomains found in reverse zones
+func sweepNetworks(domain string, index map[string]*subdomain, subdomains []*subdomain, resolver dns.Resolver, wcDetector *wildcard.Detector) []*subdomain {
+	var ips []string
+
+	for _, info := range subdomains {
+		ips = append(ips, info.ip.IP()...)
+	}
+
+	networks := reverse.Networks(ips, options.GetI(OPT_SWEEP_PREFIX))
+	names := reverse.Sweep(resolver, networks, domain, reverse.Config{
+		Workers: options.GetI(OPT_THREADS),
+		Rate:    options.GetI(OPT_BRUTE_RATE),
+		Progress: func(done, total int) {
+			fmtc.If(!useRawOutput).TPrintf(
+				"{s-}[%d/%d] Sweeping %d networks…{!}", done, total, len(networks),
+			)
+		},
+	})
+
+	fmtc.If(!useRawOutput).TPrintf("")
+
+	return addPTRSubdomains(index, subdomains, names, resolver, wcDetector)
+}
+
+// addPTRTargets adds names from PTR records of subdomains addresses which belong
+// to domain
+func addPTRTargets(domain string, index map[string]*subdomain, subdomains []*subdomain, resolver dns.Resolver, wcDetector *wildcard.Detector) []*subdomain {
+	var records dns.Records
+
+	for _, info := range subdomains {
+		records = append(records, info.records...)
+	}
+
+	return addPTRSubdomains(index, subdomains, reverse.Names(records, domain), resolver, wcDetector)
+}
+
+// addPTRSubdomains processes new subdomains found in reverse zones and returns
+// subdomains info with them
+func addPTRSubdomains(index map[string]*subdomain, subdomains []*subdomain, names []string, resolver dns.Resolver, wcDetector *wildcard.Detector) []*subdomain {
+	var found api.Subdomains
+
+	for _, name := range names {
+		if index[name] == nil {
+			found = append(found, &api.Subdomain{Name: name})
+		}
+	}
+
+	if len(found) == 0 {
+		return subdomains
+	}
+
+	newIndex := map[string]*subdomain{}
+	addSubdomains(newIndex, "ptr", found)
+	maps.Copy(index, newIndex)
+
+	result := map[string]*subdomain{}
+
+	for _, info := range subdomains {
+		result[info.name] = info
+	}
+
+	for _, info := range processSubdomains(sortSubdomains(newIndex), resolver, wcDetector) {
+		result[info.name] = info
+	}
+
+	return sortSubdomains(result)
+}
+
+// checkTakeovers checks subdomains with CNAME records for possible takeover
+func checkTakeovers(subdomains []*subdomain, resolver dns.Resolver) {
+	fingerprints, _ := getFingerprints()
+	checker := &takeover.Checker{Resolver: resolver, Fingerprints: fingerprints}
+
+	for index, info := range subdomains {
+		if info.ip == nil || info.wildcard != nil {
+			continue
+		}
+
+		fmtc.If(!useRawOutput).TPrintf(
+			"{s-}[%d/%d] Checking %s for takeover…{!}",
+			index, len(subdomains), info.name,
+		)
+
+		info.takeover = checker.Check(info.name, info.ip)
+	}
+
+	fmtc.If(!useRawOutput).TPrintf("")
+}
+
+// checkDNSSEC sets DNSSEC status of subdomains answers. Without local validation
+// status is based on AD flag set by validating resolver. Answer without AD flag
+// is indeterminate, because resolver may not validate answers at all.
+func checkDNSSEC(subdomains []*subdomain, resolver dns.Resolver) *dnssec.Validator {
+	var validator *dnssec.Validator
+
+	if options.GetB(OPT_DNSSEC_VALIDATE) {
+		exchanger, _ := getExchanger(resolver)
+		validator = &dnssec.Validator{Exchanger: exchanger}
+	}
+
+	for index, info := range subdomains {
+		if info.ip.IsEmpty() {
+			continue
+		}
+
+		if validator == nil {
+			info.dnssec = dnssec.STATUS_INDETERMINATE
+
+			if info.ip.AD {
+				info.dnssec = dnssec.STATUS_SECURE
+			}
+
+			continue
+		}
+
+		fmtc.If(!useRawOutput).TPrintf(
+			"{s-}[%d/%d] Validating %s DNSSEC chain…{!}",
+			index, len(subdomains), info.name,
+		)
+
+		qtype := dns.TYPE_A
+
+		if len(info.ip.IP()) == 0 {
+			qtype = dns.TYPE_AAAA
+		}
+
+		info.dnssec, _ = validator.Validate(info.name, qtype)
+	}
+
+	fmtc.If(!useRawOutput).TPrintf("")
+
+	return validator
+}
+
+// saveCache saves DNS cache to file from options
+func saveCache(resolver *dns.CachedResolver) {
+	err := resolver.Save(options.GetS(OPT_CACHE))
+
+	if err != nil {
+		terminal.Warn(err)
+	}
+}
+
+// getResolveStatus returns resolution status for given addresses answer
+func getResolveStatus(answer *dns.Answer, err error) (string, error) {
+	switch {
+	case err != nil:
+		return RESOLVE_ERROR, err
+	case answer.Status == dns.STATUS_NXDOMAIN:
+		return RESOLVE_NXDOMAIN, nil
+	case answer.Status == dns.STATUS_SERVFAIL:
+		return RESOLVE_SERVFAIL, nil
+	case answer.Status != dns.STATUS_NOERROR:
+		return RESOLVE_ERROR, fmt.Errorf("Resolver returned status %d", answer.Status)
+	case len(answer.IP()) == 0 && len(answer.IPv6()) == 0:
+		return RESOLVE_NO_ADDRESS, nil
+	}
+
+	return RESOLVE_OK, nil
+}
+
+// resolveAddresses resolves both IPv4 and IPv6 addresses of given domain
+func resolveAddresses(resolver dns.Resolver, name string) (*dns.Answer, error) {
+	ipv4, err4 := resolver.Resolve(name, dns.TYPE_A)
+	ipv6, err6 := resolver.Resolve(name, dns.TYPE_AAAA)
+
+	answer := ipv4.Merge(ipv6)
+	err := cmp.Or(err4, err6)
+
+	// Absence of addresses can't be trusted if one of lookups failed
+	if err != nil && (answer == nil || len(answer.IP())+len(answer.IPv6()) == 0) {
+		return nil, err
+	}
+
+	return answer, nil
+}
+
+// queryRecords queries records with given types for given domain
+func queryRecords(resolver dns.Resolver, name string, recordTypes []int) dns.Records {
+	var result dns.Records
+
+	for _, rtype := range recordTypes {
+		answer, err := resolver.Resolve(name, rtype)
+
+		if err != nil || answer.Status != dns.STATUS_NOERROR {
+			continue
+		}
+
+		for _, r := range answer.Records {
+			// Skip CNAME chain
+			if r.Type == rtype {
+				result = append(result, r)
+			}
+		}
+	}
+
+	return result
+}
+
+// printSubdomainsInfo prints subdomains info
+func printSubdomainsInfo(subdomains []*subdomain) {
+	fmtc.NewLine()
+
+	for _, info := range subdomains {
+		if !info.ip.IsEmpty() {
+			fmtc.Printf(
+				" {s}•{!} %s {s-}(%s){!}",
+				info.name, info.ip.ToString(false),
+			)
+		} else {
+			fmtc.Printf(" {s}•{!} %s", info.name)
+		}
+
+		if len(info.services) != 0 {
+			fmt.Print(" " + getColoredServicePorts(info.services))
+		}
+
+		if info.wildcard != nil {
+			fmtc.Printf(" {y}[wildcard]{!}")
+		}
+
+		if info.status != "" && info.status != RESOLVE_OK {
+			fmt.Print(" " + getColoredResolveStatus(info.status))
+		}
+
+		if info.takeover != nil {
+			fmtc.Printf(" {r}[takeover]{!}")
+		}
+
+		if info.dnssec != "" {
+			fmt.Print(" " + getColoredDNSSECStatus(info.dnssec))
+		}
+
+		if len(info.sources) != 0 {
+			fmtc.Printf(" {s-}← %s{!}", formatSources(info))
+		}
+
+		fmtc.NewLine()
+
+		if info.statusErr != nil {
+			fmtc.Printf("   {s-}%v{!}\n", info.statusErr)
+		}
+
+		for _, r := range info.records {
+			fmtc.Printf("   {s-}%-5s{!} %s\n", dns.TypeName(r.Type), formatRecord(r))
+		}
+	}
+
+	fmtc.NewLine()
+}
+
+// printRawSubdomainsInfo prints subdomains info for raw output
+func printRawSubdomainsInfo(subdomains []*subdomain) {
+	for _, info := range subdomains {
+		fmt.Println(info.name, info.ip.ToString(true))
+
+		if info.status != "" && info.status != RESOLVE_OK {
+			fmt.Println(info.name, "STATUS", info.status)
+		}
+
+		for _, r := range info.records {
+			fmt.Println(info.name, dns.TypeName(r.Type), r.Data)
+		}
+
+		if info.dnssec != "" {
+			fmt.Println(info.name, "DNSSEC", info.dnssec)
+		}
+	}
+}
+
+// printWildcards prints info about detected wildcard records
+func printWildcards(wildcards []*wildcard.Wildcard) {
+	if len(wildcards) == 0 {
+		return
+	}
+
+	fmtc.Println("{*}Wildcards:{!}")
+
+	for _, wc := range wildcards {
+		fmtc.Printf(" {y}•{!} %s\n", wc)
+	}
+
+	fmtc.NewLine()
+}
+
+// printTransfers prints info about allowed zone transfers
+func printTransfers(transfers []*axfr.Transfer) {
+	if len(transfers) == 0 {
+		return
+	}
+
+	if useRawOutput {
+		for _, transfer := range transfers {
+			terminal.Warn("Zone transfer allowed: %s", transfer)
+		}
+
+		return
+	}
+
+	fmtc.Println("{r*}Zone transfers allowed (misconfiguration):{!}")
+
+	for _, transfer := range transfers {
+		fmtc.Printf(" {r}•{!} %s\n", transfer)
+	}
+
+	fmtc.NewLine()
+}
+
+// printZoneWalkResult prints zone walking result
+func printZoneWalkResult(result *zonewalk.Result, err error) {
+	if result == nil && err == nil {
+		return
+	}
+
+	fmtc.Println("{*}Zone walking:{!}")
+
+	switch {
+	case result == nil:
+		fmtc.Printf(" {r}•{!} %v\n", err)
+	case len(result.Hashes) != 0:
+		fmtc.Printf(
+			" {y}•{!} %s: %d NSEC3 hashes collected, %d cracked\n",
+			result.Zone, len(result.Hashes), len(result.Names),
+		)
+	default:
+		fmtc.Printf(
+			" {y}•{!} %s: %d names found in NSEC chain\n",
+			result.Zone, len(result.Names),
+		)
+	}
+
+	if result != nil && err != nil {
+		fmtc.Printf(" {r}•{!} Walking was interrupted: %v\n", err)
+	} else if result != nil && !result.Complete {
+		fmtc.Println(" {s}•{!} {s}Chain of hashes wasn't collected completely{!}")
+	}
+
+	fmtc.NewLine()
+}
+
+// printTakeovers prints info about subdomains which can be taken over
+func printTakeovers(subdomains []*subdomain) {
+	var candidates []*takeover.Candidate
+
+	for _, info := range subdomains {
+		if info.takeover != nil {
+			candidates = append(candidates, info.takeover)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	if useRawOutput {
+		for _, candidate := range candidates {
+			terminal.Warn("Takeover candidate: %s", candidate)
+		}
+
+		return
+	}
+
+	fmtc.Println("{r*}Takeover candidates:{!}")
+
+	for _, candidate := range candidates {
+		fmtc.Printf(" {r}•{!} %s\n", candidate)
+	}
+
+	fmtc.NewLine()
+}
+
+// printDNSSECZones prints DNSSEC validation status of zones
+func printDNSSECZones(validator *dnssec.Validator) {
+	if validator == nil {
+		return
+	}
+
+	var zones []*dnssec.Zone
+
+	for _, zone := range validator.Zones() {
+		// Root zone is always validated using built-in trust anchors
+		if zone.Name != "." {
+			zones = append(zones, zone)
+		}
+	}
+
+	if len(zones) == 0 {
+		return
+	}
+
+	fmtc.Println("{*}DNSSEC:{!}")
+
+	for _, zone := range zones {
+		if zone.Error != nil {
+			fmtc.Printf(
+				" {s}•{!} %s %s {s-}— %v{!}\n",
+				zone.Name, getColoredDNSSECStatus(zone.Status), zone.Error,
+			)
+		} else {
+			fmtc.Printf(" {s}•{!} %s %s\n", zone.Name, getColoredDNSSECStatus(zone.Status))
+		}
+	}
+
+	fmtc.NewLine()
+}
+
+// printCacheStats prints number of queries answered from DNS cache
+func printCacheStats(resolver *dns.CachedResolver) {
+	hits, misses := resolver.Stats()
+
+	if useRawOutput || !options.Has(OPT_CACHE) || hits+misses == 0 {
+		return
+	}
+
+	fmtc.Println("{*}DNS cache:{!}")
+	fmtc.Printf(
+		" {s}•{!} Queries: %d {s-}(from cache: %d, sent to resolver: %d){!}\n",
+		hits+misses, hits, misses,
+	)
+	fmtc.NewLine()
+}
+
+// printResolversHealth prints health of resolvers from pool
+func printResolversHealth(resolver dns.Resolver) {
+	pool, ok := resolver.(*dns.Pool)
+
+	if !ok {
+		return
+	}
+
+	if useRawOutput {
+		for _, h := range pool.Health() {
+			if h.Ejected {
+				terminal.Warn("DNS provider %s is unhealthy", h.Name)
+			}
+		}
+
+		return
+	}
+
+	fmtc.Println("{*}DNS providers:{!}")
+
+	for _, h := range pool.Health() {
+		switch {
+		case h.Ejected:
+			fmtc.Printf(
+				" {r}✖{!} %s {s-}— unhealthy (queries: %d, failures: %d, ejections: %d){!}\n",
+				h.Name, h.Queries, h.Failures, h.Ejections,
+			)
+		case h.Failures != 0:
+			fmtc.Printf(
+				" {y}!{!} %s {s-}(queries: %d, failures: %d, ejections: %d){!}\n",
+				h.Name, h.Queries, h.Failures, h.Ejections,
+			)
+		default:
+			fmtc.Printf(" {g}✔{!} %s {s-}(queries: %d){!}\n", h.Name, h.Queries)
+		}
+	}
+
+	fmtc.NewLine()
+}
+
+// printSourcesStatus prints status of every source
+func printSourcesStatus(results []*api.Result) {
+	slices.SortFunc(results, func(a, b *api.Result) int {
+		return strings.Compare(a.Source.Name(), b.Source.Name())
+	})
+
+	if useRawOutput {
+		for _, res := range results {
+			if isSourceFailed(res) || res.Status() == api.STATUS_TRUNCATED {
+				terminal.Warn("%s: %v", res.Source.Name(), res.Error)
+			}
+		}
+
+		return
+	}
+
+	fmtc.Println("{*}Sources:{!}")
+
+	for _, res := range results {
+		dur := res.Duration.Round(time.Millisecond)
+
+		switch res.Status() {
+		case api.STATUS_OK:
+			fmtc.Printf(
+				" {g}✔{!} %s {s-}(found: %d in %s){!}\n",
+				res.Source.Name(), len(res.Subdomains), dur,
+			)
+		case api.STATUS_RATE_LIMITED:
+			fmtc.Printf(
+				" {y}!{!} %s {s-}— rate limited: %v{!}\n",
+				res.Source.Name(), res.Error,
+			)
+		case api.STATUS_TRUNCATED:
+			fmtc.Printf(
+				" {y}!{!} %s {s-}(found: %d in %s) — truncated: %v{!}\n",
+				res.Source.Name(), len(res.Subdomains), dur, res.Error,
+			)
+		case api.STATUS_SKIPPED:
+			fmtc.Printf(
+				" {s}–{!} %s {s-}— skipped: %v{!}\n",
+				res.Source.Name(), res.Error,
+			)
+		default:
+			fmtc.Printf(
+				" {r}✖{!} %s {s-}— failed: %v{!}\n",
+				res.Source.Name(), res.Error,
+			)
+		}
+	}
+
+	fmtc.NewLine()
+}
+
+// getResolver returns resolver for providers or URLs from options. Every provider
+// has its own queries rate limit, and several providers are combined into pool.
+func getResolver() (dns.Resolver, error) {
+	providers := getProviders()
+	pool := &dns.Pool{}
+
+	if len(providers) == 0 {
+		return nil, fmt.Errorf("DNS providers list is empty")
+	}
+
+	for _, provider := range providers {
+		resolver, err := getProviderResolver(provider)
+
+		if err != nil {
+			return nil, err
+		}
+
+		resolver = dns.Limit(resolver, options.GetI(OPT_QPS))
+
+		if len(providers) == 1 {
+			return resolver, nil
+		}
+
+		pool.Add(provider, resolver)
+	}
+
+	return pool, nil
+}
+
+// getProviderResolver returns resolver for provider or URL
+func getProviderResolver(provider string) (dns.Resolver, error) {
+	resolverURL, ok := dohProviders[provider]
+
+	if ok {
+		return &dns.DoHResolver{URL: resolverURL, Format: dns.DOH_JSON}, nil
+	}
+
+	return dns.NewResolver(provider)
+}
+
+// getProviders returns list of DNS providers from options
+func getProviders() []string {
+	var result []string
+
+	for _, provider := range strings.Split(options.GetS(OPT_DNS), ",") {
+		provider = strings.TrimSpace(provider)
+
+		if provider != "" {
+			result = append(result, provider)
+		}
+	}
+
+	return result
+}
+
+// getExchanger returns wire-format resolver for DNSSEC validation. JSON API
+// doesn't return DNSSEC records, so RFC 8484 endpoint of provider is used instead.
+func getExchanger(resolver dns.Resolver) (dns.Exchanger, error) {
+	resolver = unwrapResolver(resolver)
+	doh, ok := resolver.(*dns.DoHResolver)
+
+	if ok && doh.Format == dns.DOH_JSON {
+		url := doh.URL
+
+		if dohWireURLs[url] != "" {
+			url = dohWireURLs[url]
+		}
+
+		return &dns.DoHResolver{URL: url, Format: dns.DOH_GET}, nil
+	}
+
+	exchanger, ok := resolver.(dns.Exchanger)
+
+	if !ok {
+		return nil, fmt.Errorf("Resolver doesn't support wire format required for DNSSEC validation")
+	}
+
+	return exchanger, nil
+}
+
+// unwrapResolver returns upstream resolver wrapped by cache and rate limiter.
+// Wrappers work only with parsed answers, so raw messages are sent to upstream.
+// For pool of resolvers the first provider is used.
+func unwrapResolver(resolver dns.Resolver) dns.Resolver {
+	for {
+		switch r := resolver.(type) {
+		case *dns.CachedResolver:
+			resolver = r.Resolver
+		case *dns.LimitedResolver:
+			resolver = r.Resolver
+		case *dns.Pool:
+			members := r.Resolvers()
+
+			if len(members) == 0 {
+				return resolver
+			}
+
+			resolver = members[0]
+		default:
+			return resolver
+		}
+	}
+}
+
+// closeResolver closes connections of resolver and all resolvers wrapped by it
+func closeResolver(resolver dns.Resolver) {
+	switch r := resolver.(type) {
+	case *dns.CachedResolver:
+		closeResolver(r.Resolver)
+	case *dns.LimitedResolver:
+		closeResolver(r.Resolver)
+	case *dns.Pool:
+		for _, member := range r.Resolvers() {
+			closeResolver(member)
+		}
+	case io.Closer:
+		r.Close()
+	}
+}
+
+// parseRange parses range of CT log entries indexes ("start-end")
+func parseRange(r string) (int64, int64, error) {
+	if r == "" {
+		return 0, 0, nil
+	}
+
+	startStr, endStr, _ := strings.Cut(r, "-")
+	start, err := strconv.ParseInt(startStr, 10, 64)
+
+	if err != nil || start < 0 {
+		return 0, 0, fmt.Errorf("Invalid range start in %q", r)
+	}
+
+	end, err := strconv.ParseInt(endStr, 10, 64)
+
+	if err != nil || end < start {
+		return 0, 0, fmt.Errorf("Invalid range end in %q", r)
+	}
+
+	return start, end, nil
+}
+
+// isSourceFailed returns true if source returned an error
+func isSourceFailed(res *api.Result) bool {
+	status := res.Status()
+	return status == api.STATUS_FAILED || status == api.STATUS_RATE_LIMITED
+}
+
+// isAddressesRequired returns true if subdomains addresses must be resolved
+func isAddressesRequired() bool {
+	return options.GetB(OPT_IP) || options.GetB(OPT_PROBE) ||
+		options.GetB(OPT_PTR) || options.GetB(OPT_SWEEP) ||
+		options.GetB(OPT_TAKEOVER) || options.GetB(OPT_DNSSEC) ||
+		options.GetB(OPT_DNSSEC_VALIDATE) || options.GetB(OPT_RESOLVED) ||
+		options.GetB(OPT_DEAD) || options.GetB(OPT_NO_WC)
+}
+
+// getFingerprints returns takeover fingerprints database
+func getFingerprints() ([]*takeover.Fingerprint, error) {
+	if !options.Has(OPT_TAKEOVER_DB) {
+		return takeover.DefaultFingerprints(), nil
+	}
+
+	return takeover.ReadFingerprints(options.GetS(OPT_TAKEOVER_DB))
+}
+
+// getWordlist returns wordlist for brute-force
+func getWordlist() ([]string, error) {
+	if options.GetS(OPT_BRUTE) == "default" {
+		return brute.DefaultWordlist(), nil
+	}
+
+	return brute.ReadWordlist(options.GetS(OPT_BRUTE))
+}
+
+// getRecordTypes returns record types from options
+func getRecordTypes() ([]int, error) {
+	if !options.Has(OPT_RECORDS) {
+		return nil, nil
+	}
+
+	var result []int
+
+	for _, name := range strings.Split(options.GetS(OPT_RECORDS), ",") {
+		rtype, ok := dns.ParseType(name)
+
+		if !ok {
+			return nil, fmt.Errorf("Unknown record type %q", name)
+		}
+
+		if !slices.Contains(result, rtype) {
+			result = append(result, rtype)
+		}
+	}
+
+	return result, nil
+}
+
+// formatRecord formats record data using typed views
+func formatRecord(r *dns.Record) string {
+	switch r.Type {
+	case dns.TYPE_MX:
+		if mx := r.MX(); mx != nil {
+			return fmtc.Sprintf("%s {s-}(preference: %d){!}", mx.Exchange, mx.Preference)
+		}
+	case dns.TYPE_TXT:
+		return strutil.JoinFunc(r.TXT(), " ", func(s string) string {
+			return strconv.Quote(s)
+		})
+	case dns.TYPE_SOA:
+		if soa := r.SOA(); soa != nil {
+			return fmtc.Sprintf(
+				"%s %s {s-}(serial: %d, refresh: %d, retry: %d, expire: %d, minimum: %d){!}",
+				soa.MName, soa.RName, soa.Serial, soa.Refresh, soa.Retry, soa.Expire, soa.Minimum,
+			)
+		}
+	case dns.TYPE_SRV:
+		if srv := r.SRV(); srv != nil {
+			return fmtc.Sprintf(
+				"%s:%d {s-}(priority: %d, weight: %d){!}",
+				srv.Target, srv.Port, srv.Priority, srv.Weight,
+			)
+		}
+	case dns.TYPE_CAA:
+		if caa := r.CAA(); caa != nil {
+			return fmtc.Sprintf("%s %s {s-}(flags: %d){!}", caa.Tag, caa.Value, caa.Flags)
+		}
+	}
+
+	return r.Data
+}
+
+// formatSources formats info about subdomain sources
+func formatSources(info *subdomain) string {
+	result := strings.Join(info.sources, ", ")
+
+	if len(info.issuers) != 0 {
+		result += " / " + strings.Join(info.issuers, ", ")
+	}
+
+	switch {
+	case info.firstSeen.IsZero():
+		return result
+	case info.firstSeen.Equal(info.lastSeen):
+		return result + " (" + info.firstSeen.Format(time.DateOnly) + ")"
+	}
+
+	return result + " (" + info.firstSeen.Format(time.DateOnly) +
+		" – " + info.lastSeen.Format(time.DateOnly) + ")"
+}
+
+// getColoredServicePorts formats list of services
+func getColoredServicePorts(services []string) string {
+	return strutil.JoinFunc(services, " ", func(s string) string {
+		colorTag := "{#152}"
+
+		switch s {
+		case "22", "23", "5800":
+			colorTag = "{#67}"
+		case "25", "110", "143", "220", "993", "995":
+			colorTag = "{#173}"
+		case "21", "115", "445", "636", "990", "3389":
+			colorTag = "{#140}"
+		case "80", "443", "3000", "8080", "8443", "9000":
+			colorTag = "{#151}"
+		case "53":
+			colorTag = "{#153}"
+		case "1434", "3306", "3690", "5432", "6379", "6432", "9042", "27017":
+			colorTag = "{#221}"
+		}
+
+		return fmtc.Sprintf(colorTag+"[%s]{!}", s)
+	})
+}
+
+// getColoredResolveStatus returns colored resolution status tag
+func getColoredResolveStatus(status string) string {
+	switch status {
+	case RESOLVE_NXDOMAIN, RESOLVE_ERROR:
+		return fmtc.Sprintf("{r}[%s]{!}", status)
+	case RESOLVE_SERVFAIL:
+		return fmtc.Sprintf("{y}[%s]{!}", status)
+	}
+
+	return fmtc.Sprintf("{s}[%s]{!}", status)
+}
+
+// getColoredDNSSECStatus returns colored DNSSEC status tag
+func getColoredDNSSECStatus(status string) string {
+	switch status {
+	case dnssec.STATUS_SECURE:
+		return fmtc.Sprintf("{g}[%s]{!}", status)
+	case dnssec.STATUS_BOGUS:
+		return fmtc.Sprintf("{r}[%s]{!}", status)
+	case dnssec.STATUS_INDETERMINATE:
+		return fmtc.Sprintf("{y}[%s]{!}", status)
+	}
+
+	return fmtc.Sprintf("{s}[%s]{!}", status)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// checkAPIAvailability checks API availability
+func checkAPIAvailability() support.Check {
+	chk := support.Check{support.CHECK_ERROR, "subdomain.center API", ""}
+
+	start := time.Now()
+	resp, err := req.Request{
+		URL:         "https://api.subdomain.center",
+		AutoDiscard: true,
+	}.Get()
+	dur := time.Since(start)
+
+	if err != nil {
+		chk.Message = "Can't send request"
+		return chk
+	}
+
+	if resp.StatusCode != 200 {
+		chk.Message = fmt.Sprintf("API returned non-ok status code (%d)", resp.StatusCode)
+		return chk
+	}
+
+	chk.Status = support.CHECK_OK
+
+	if dur < 500*time.Millisecond {
+		chk.Message = "accessible and healthy"
+	} else {
+		chk.Message = "accessible"
+	}
+
+	return chk
+}
+
+// printCompletion prints completion for given shell
+func printCompletion() int {
+	info := genUsage()
+
+	switch options.GetS(OPT_COMPLETION) {
+	case "bash":
+		fmt.Print(bash.Generate(info, APP))
+	case "fish":
+		fmt.Print(fish.Generate(info, APP))
+	case "zsh":
+		fmt.Print(zsh.Generate(info, optMap, APP))
+	default:
+		return 1
+	}
+
+	return 0
+}
+
+// printMan prints man page
+func printMan() {
+	fmt.Println(man.Generate(genUsage(), genAbout("")))
+}
+
+// genUsage generates usage info
+func genUsage() *usage.Info {
+	info := usage.NewInfo("", "domain")
+
+	info.AppNameColorTag = colorTagApp
+
+	info.AddOption(OPT_IP, "Resolve subdomains IPv4 and IPv6 addresses")
+	info.AddOption(OPT_DNS, "Comma-separated list of DNS providers {s-}({_}cloudflare{!_}|google|quad9|https://…|udp://…|tcp://…|tls://…){!}", "name-or-url")
+	info.AddOption(OPT_PROBE, "Probe subdomains for open ports")
+	info.AddOption(OPT_RECORDS, "Query records with given types {s-}(comma-separated, e.g. mx,txt,ns,caa,srv,soa){!}", "types")
+	info.AddOption(OPT_NO_WC, "Drop subdomains matching wildcard records")
+	info.AddOption(OPT_RESOLVED, "Show only subdomains with A or AAAA records")
+	info.AddOption(OPT_DEAD, "Show only subdomains which don't exist anymore {s-}(NXDOMAIN){!}")
+	info.AddOption(OPT_BRUTE, "Brute-force subdomains using wordlist {s-}(file or \"default\" for built-in wordlist){!}", "wordlist")
+	info.AddOption(OPT_RECURSE, "Recursively search subdomains of found sub-zones {s-}(1-10){!}", "depth")
+	info.AddOption(OPT_AXFR, "Attempt zone transfers (AXFR) from authoritative name servers {s-}(plain DNS queries are sent directly to name servers regardless of --dns){!}")
+	info.AddOption(OPT_PTR, "Look up PTR records for subdomains addresses")
+	info.AddOption(OPT_SWEEP, "Look up PTR records in networks around subdomains addresses")
+	info.AddOption(OPT_TAKEOVER, "Check CNAME records of subdomains for possible takeover")
+	info.AddOption(OPT_DNSSEC, "Show DNSSEC validation status of subdomains answers {s-}(secure if resolver set AD flag, indeterminate otherwise){!}")
+	info.AddOption(OPT_CACHE, "Keep DNS answers cache in file between runs", "file")
+	info.AddOption(OPT_THREADS, "Number of concurrent DNS workers {s-}(1-512, default: 16){!}", "num")
+	info.AddOption(OPT_QPS, "Maximum number of DNS queries per second {s-}(0 = no limit, default: 200){!}", "qps")
+	info.AddOption(OPT_PERMUTE, "Resolve permutations of found subdomains {s-}(api-dev → api-stage, web01 → web02){!}")
+	info.AddOption(OPT_TIMEOUT, "Timeout for every source in seconds {s-}(1-3600, default: 30){!}", "sec")
+	info.AddOption(OPT_DEADLINE, "Deadline for search using all sources in seconds {s-}(default: 120){!}", "sec")
+	info.AddOption(OPT_STRICT, "Fail if any source returned an error")
+	info.AddOption(OPT_CERTSPOTTER_PAGES, "Maximum number of CertSpotter API pages to fetch {s-}(results over limit are truncated, 0 = no limit, default: 10){!}", "num")
+	info.AddOption(OPT_CERTSPOTTER_HISTORY, "Fetch full certificates issuance history from CertSpotter API")
+	info.AddOption(OPT_CT_LOGS, "Search subdomains directly in CT logs from log list {s-}(Chrome/Apple JSON format){!}", "file")
+	info.AddOption(OPT_CT_RANGE, "Range of CT log entries indexes {s-}(default: the latest 10000){!}", "start-end")
+	info.AddOption(OPT_BRUTE_RATE, "Maximum number of brute-force and permutation queries per second {s-}(0 = no limit, default: 100){!}", "qps")
+	info.AddOption(OPT_ZONE_WALK, "Enumerate names of DNSSEC-signed zone using NSEC/NSEC3 records {s-}(plain DNS queries are sent directly to name servers regardless of --dns){!}")
+	info.AddOption(OPT_SWEEP_PREFIX, "Prefix length of swept networks {s-}(16-32, default: 24){!}", "bits")
+	info.AddOption(OPT_TAKEOVER_DB, "Custom takeover fingerprints database {s-}(JSON){!}", "file")
+	info.AddOption(OPT_PERMUTE_LIMIT, "Maximum number of permutations {s-}(default: 5000){!}", "num")
+	info.AddOption(OPT_DNSSEC_VALIDATE, "Validate DNSSEC chain of trust locally from root zone")
+	info.AddOption(OPT_NO_COLOR, "Disable colors in output")
+	info.AddOption(OPT_HELP, "Show this help message")
+	info.AddOption(OPT_VER, "Show version")
+
+	info.AddEnv(ENV_SUBDOMAINS, "Token for subdomain.center API")
+	info.AddEnv(ENV_CERT_SPOTTER, "Token for CertSpotter API")
+
+	info.AddExample(
+		"go.dev", "Find all subdomains of go.dev",
+	)
+
+	info.AddExample(
+		"-I go.dev", "Find all subdomains of go.dev and resolve their IPs",
+	)
 
 	info.AddExample(
 		"-I -D google go.dev", "Find all subdomains of go.dev and resolve their IPs using Google DNS",
 	)
 
+	info.AddExample(
+		"-I -D cloudflare,google,quad9 go.dev", "Find all subdomains of go.dev and resolve their IPs using pool of DNS providers",
+	)
+
+	info.AddExample(
+		"--only-dead go.dev", "Find subdomains of go.dev which don't exist anymore",
+	)
+
+	info.AddExample(
+		"-R mx,txt,ns go.dev", "Find all subdomains of go.dev and show their MX, TXT and NS records",
+	)
+
+	info.AddExample(
+		"-B default -I go.dev", "Find all subdomains of go.dev including brute-forced using built-in wordlist",
+	)
+
+	info.AddExample(
+		"-r 2 -B default corp.example.com", "Find all subdomains of corp.example.com including subdomains of found sub-zones",
+	)
+
+	info.AddExample(
+		"-A example.com", "Find all subdomains of example.com and try zone transfers for found zones",
+	)
+
+	info.AddExample(
+		"-I --ptr --sweep example.com", "Find all subdomains of example.com including names from reverse zones of their networks",
+	)
+
+	info.AddExample(
+		"--takeover example.com", "Find all subdomains of example.com and check them for possible takeover",
+	)
+
+	info.AddExample(
+		"-M -I go.dev", "Find all subdomains of go.dev and their likely siblings",
+	)
+
+	info.AddExample(
+		"-I -D udp://10.0.0.53 go.dev", "Find all subdomains of go.dev and resolve their IPs using plain DNS server",
+	)
+
+	info.AddExample(
+		"-I -D https://doh.example.com/dns-query#post go.dev", "Find all subdomains of go.dev and resolve their IPs using RFC 8484 POST requests",
+	)
+
+	info.AddExample(
+		"-I -D tls://1.1.1.1#one.one.one.one go.dev", "Find all subdomains of go.dev and resolve their IPs using DNS-over-TLS",
+	)
+
+	info.AddExample(
+		"--ct-logs log_list.json --ct-range 0-50000 go.dev",
+		"Find all subdomains of go.dev including first 50000 entries from CT logs",
+	)
+
 	return info
 }
 
@@ -0,0 +1,96 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MAX_TRANSFER_MESSAGES is maximum number of messages in zone transfer
+const MAX_TRANSFER_MESSAGES = 100000
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Transfer requests full zone transfer (AXFR) from given server over TCP and
+// returns all zone records
+func Transfer(addr, zone string, timeout time.Duration) (Records, error) {
+	if timeout <= 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+
+	query := NewQuery(zone, TYPE_AXFR)
+	query.Flags = 0 // Zone transfer is non-recursive query
+
+	data, err := query.Pack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't connect to %s: %w", addr, err)
+	}
+
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	err = writeStreamMessage(conn, data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't send query to %s: %w", addr, err)
+	}
+
+	var result Records
+
+	// Transfer starts with SOA record and ends with the same SOA record
+	for range MAX_TRANSFER_MESSAGES {
+		// Large zones can be transferred slower than single query
+		conn.SetDeadline(time.Now().Add(timeout))
+
+		data, err = readStreamMessage(conn)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't read response from %s: %w", addr, err)
+		}
+
+		resp, err := Unpack(data)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !resp.IsResponseTo(query) {
+			return nil, fmt.Errorf("Server %s returned response to another query", addr)
+		}
+
+		status := int(resp.Flags & 0xF)
+
+		if status != STATUS_NOERROR {
+			return nil, fmt.Errorf("Server %s refused zone transfer (status: %d)", addr, status)
+		}
+
+		if len(result) == 0 && (len(resp.Answer) == 0 || resp.Answer[0].Type != TYPE_SOA) {
+			return nil, fmt.Errorf("Server %s returned invalid zone transfer response", addr)
+		}
+
+		result = append(result, resp.Answer...)
+
+		if len(result) > 1 && result[len(result)-1].Type == TYPE_SOA {
+			return result, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Zone transfer from %s is too large", addr)
+}
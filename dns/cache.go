@@ -0,0 +1,323 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MAX_CACHE_TTL is maximum time answer can be stored in cache
+const MAX_CACHE_TTL = 24 * time.Hour
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CachedResolver is resolver wrapper which caches answers using records TTL.
+// Negative answers are cached using SOA record from authority section (RFC 2308).
+// Concurrent identical queries are sent to resolver only once. TTL of records
+// from cached answers is decreased by time they spent in cache.
+type CachedResolver struct {
+	Resolver Resolver
+
+	entries map[string]*cacheEntry
+	calls   map[string]*cacheCall
+	hits    int
+	misses  int
+	mu      sync.Mutex
+}
+
+// cacheEntry is cached answer
+type cacheEntry struct {
+	Answer  *Answer   `json:"answer"`
+	Stored  time.Time `json:"stored"`
+	Expires time.Time `json:"expires"`
+}
+
+// cacheCall is in-flight query
+type cacheCall struct {
+	wg     sync.WaitGroup
+	answer *Answer
+	err    error
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Cache returns resolver which caches answers of given resolver
+func Cache(resolver Resolver) *CachedResolver {
+	return &CachedResolver{
+		Resolver: resolver,
+		entries:  map[string]*cacheEntry{},
+		calls:    map[string]*cacheCall{},
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Resolve returns info about given domain
+func (r *CachedResolver) Resolve(domain string, qtype int) (*Answer, error) {
+	key := cacheKey(domain, qtype)
+
+	r.mu.Lock()
+
+	now := time.Now()
+	entry := r.entries[key]
+
+	if entry != nil && now.Before(entry.Expires) {
+		r.hits++
+		r.mu.Unlock()
+		return copyAnswer(entry.Answer, now.Sub(entry.Stored)), nil
+	}
+
+	call := r.calls[key]
+
+	if call != nil {
+		r.hits++
+		r.mu.Unlock()
+		call.wg.Wait()
+		return copyAnswer(call.answer, 0), call.err
+	}
+
+	call = &cacheCall{}
+	call.wg.Add(1)
+	r.calls[key] = call
+	r.misses++
+
+	r.mu.Unlock()
+
+	call.answer, call.err = r.Resolver.Resolve(domain, qtype)
+
+	r.mu.Lock()
+
+	if call.err == nil {
+		r.store(key, qtype, call.answer)
+	}
+
+	delete(r.calls, key)
+
+	r.mu.Unlock()
+
+	call.wg.Done()
+
+	return copyAnswer(call.answer, 0), call.err
+}
+
+// Stats returns number of queries answered from cache and number of queries sent
+// to resolver
+func (r *CachedResolver) Stats() (int, int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.hits, r.misses
+}
+
+// Load loads cached answers from file. Missing file isn't an error.
+func (r *CachedResolver) Load(file string) error {
+	data, err := os.ReadFile(file)
+
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("Can't read cache file: %w", err)
+	}
+
+	entries := map[string]*cacheEntry{}
+	err = json.Unmarshal(data, &entries)
+
+	if err != nil {
+		return fmt.Errorf("Can't decode cache file: %w", err)
+	}
+
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for key, entry := range entries {
+		if entry != nil && entry.Answer != nil && !entry.Stored.IsZero() && now.Before(entry.Expires) {
+			r.entries[key] = entry
+		}
+	}
+
+	return nil
+}
+
+// Save saves not expired cached answers to file
+func (r *CachedResolver) Save(file string) error {
+	now := time.Now()
+	entries := map[string]*cacheEntry{}
+
+	r.mu.Lock()
+
+	for key, entry := range r.entries {
+		if now.Before(entry.Expires) {
+			entries[key] = entry
+		}
+	}
+
+	data, err := json.Marshal(entries)
+
+	r.mu.Unlock()
+
+	if err != nil {
+		return fmt.Errorf("Can't encode cache: %w", err)
+	}
+
+	err = os.MkdirAll(filepath.Dir(file), 0700)
+
+	if err != nil {
+		return fmt.Errorf("Can't create cache directory: %w", err)
+	}
+
+	err = os.WriteFile(file, data, 0600)
+
+	if err != nil {
+		return fmt.Errorf("Can't write cache file: %w", err)
+	}
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// store adds answer to cache. Records from CNAME chain are also cached as answers
+// for their owners, so shared CNAME targets are resolved only once.
+func (r *CachedResolver) store(key string, qtype int, answer *Answer) {
+	ttl, ok := getCacheTTL(answer)
+
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	r.entries[key] = &cacheEntry{Answer: answer, Stored: now, Expires: now.Add(ttl)}
+
+	if answer.Status != STATUS_NOERROR {
+		return
+	}
+
+	owners := map[string]Records{}
+
+	for _, rec := range answer.Records {
+		ownerKey := cacheKey(rec.Name, qtype)
+
+		if rec.Type == qtype && ownerKey != key {
+			owners[ownerKey] = append(owners[ownerKey], rec)
+		}
+	}
+
+	for ownerKey, records := range owners {
+		ttl := getRecordsTTL(records)
+
+		if ttl > 0 && r.entries[ownerKey] == nil {
+			r.entries[ownerKey] = &cacheEntry{
+				Answer:  &Answer{Status: answer.Status, AD: answer.AD, Records: records},
+				Stored:  now,
+				Expires: now.Add(ttl),
+			}
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// getCacheTTL returns time answer can be cached
+func getCacheTTL(answer *Answer) (time.Duration, bool) {
+	var ttl time.Duration
+
+	switch {
+	case answer == nil:
+		return 0, false
+
+	case answer.Status == STATUS_NOERROR && len(answer.Records) != 0:
+		ttl = getRecordsTTL(answer.Records)
+
+	case answer.Status == STATUS_NOERROR, answer.Status == STATUS_NXDOMAIN:
+		// Negative answer TTL is minimum of SOA TTL and SOA MINIMUM field
+		// (RFC 2308, section 5)
+		for _, rec := range answer.Authority {
+			soa := rec.SOA()
+
+			if soa != nil {
+				ttl = time.Duration(min(rec.TTL, int(soa.Minimum))) * time.Second
+				break
+			}
+		}
+
+	default:
+		// Server failures aren't cached
+		return 0, false
+	}
+
+	if ttl <= 0 {
+		return 0, false
+	}
+
+	return min(ttl, MAX_CACHE_TTL), true
+}
+
+// getRecordsTTL returns minimal TTL of records
+func getRecordsTTL(records Records) time.Duration {
+	ttl := -1
+
+	for _, rec := range records {
+		if ttl < 0 || rec.TTL < ttl {
+			ttl = rec.TTL
+		}
+	}
+
+	return time.Duration(ttl) * time.Second
+}
+
+// copyAnswer returns copy of answer with records TTL decreased by given time
+func copyAnswer(answer *Answer, elapsed time.Duration) *Answer {
+	if answer == nil {
+		return nil
+	}
+
+	result := *answer
+
+	result.Records = copyRecords(answer.Records, elapsed)
+	result.Authority = copyRecords(answer.Authority, elapsed)
+
+	return &result
+}
+
+// copyRecords returns copy of records with TTL decreased by given time
+func copyRecords(records Records, elapsed time.Duration) Records {
+	if records == nil {
+		return nil
+	}
+
+	result := make(Records, len(records))
+	elapsedSec := max(int(elapsed/time.Second), 0)
+
+	for index, rec := range records {
+		r := *rec
+		r.TTL = max(r.TTL-elapsedSec, 0)
+		result[index] = &r
+	}
+
+	return result
+}
+
+// cacheKey returns cache key for given query
+func cacheKey(domain string, qtype int) string {
+	return strings.ToLower(strings.TrimRight(domain, ".")) + "/" + strconv.Itoa(qtype)
+}
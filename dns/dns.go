@@ -9,9 +9,10 @@ package dns
 
 import (
 	"fmt"
+	"net"
+	"net/netip"
+	"slices"
 	"strings"
-
-	"github.com/essentialkaos/ek/v13/req"
 )
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -22,23 +23,74 @@ const (
 	QUAD9      = "9.9.9.9:5053/dns-query"
 )
 
+// Record types
+const (
+	TYPE_A          = 1
+	TYPE_NS         = 2
+	TYPE_CNAME      = 5
+	TYPE_SOA        = 6
+	TYPE_PTR        = 12
+	TYPE_MX         = 15
+	TYPE_TXT        = 16
+	TYPE_AAAA       = 28
+	TYPE_SRV        = 33
+	TYPE_DNAME      = 39
+	TYPE_OPT        = 41
+	TYPE_DS         = 43
+	TYPE_RRSIG      = 46
+	TYPE_NSEC       = 47
+	TYPE_DNSKEY     = 48
+	TYPE_NSEC3      = 50
+	TYPE_NSEC3PARAM = 51
+	TYPE_AXFR       = 252
+	TYPE_CAA        = 257
+)
+
+// Response statuses (RCODE)
+const (
+	STATUS_NOERROR  = 0
+	STATUS_FORMERR  = 1
+	STATUS_SERVFAIL = 2
+	STATUS_NXDOMAIN = 3
+	STATUS_NOTIMP   = 4
+	STATUS_REFUSED  = 5
+)
+
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// Resolver is DoH resolver
-type Resolver struct {
-	URL string
+// Resolver is DNS resolver
+type Resolver interface {
+	// Resolve sends query with given record type for given domain
+	Resolve(domain string, qtype int) (*Answer, error)
+}
+
+// Exchanger is wire-format resolver which can send raw DNS messages
+type Exchanger interface {
+	// Exchange sends query to server and returns response
+	Exchange(query *Message) (*Message, error)
 }
 
 // Answer is resolver answer
 type Answer struct {
-	Status  int     `json:"Status"`
-	Records Records `json:"Answer"`
+	Status    int         `json:"Status"`
+	TC        bool        `json:"TC"` // Truncated
+	RD        bool        `json:"RD"` // Recursion desired
+	RA        bool        `json:"RA"` // Recursion available
+	AD        bool        `json:"AD"` // Answer was validated with DNSSEC by resolver
+	CD        bool        `json:"CD"` // DNSSEC validation was disabled
+	Question  []*Question `json:"Question"`
+	Records   Records     `json:"Answer"`
+	Authority Records     `json:"Authority"`
 }
 
 // Record is DNS record
 type Record struct {
-	Data string `json:"data"`
+	Name string `json:"name"`
 	Type int    `json:"type"`
+	TTL  int    `json:"TTL"`
+	Data string `json:"data"`
+
+	rdata []byte
 }
 
 // Records is a slice with records
@@ -46,44 +98,124 @@ type Records []*Record
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// resolveError is resolving error
-type resolveError struct {
-	Error string `json:"error"`
-}
+// NewResolver creates new resolver for given URL. Supported URL schemes:
+//
+//   - udp://host[:port] — plain DNS over UDP with fallback to TCP
+//   - tcp://host[:port] — plain DNS over TCP
+//   - tls://host[:port][#server-name] — DNS-over-TLS
+//   - https://host/path — DNS-over-HTTPS (scheme can be omitted)
+//
+// DoH request format is detected automatically, but it can be forced using URL
+// fragment: #json (JSON API), #get or #post (RFC 8484).
+func NewResolver(url string) (Resolver, error) {
+	scheme, addr, ok := strings.Cut(url, "://")
+
+	if !ok {
+		scheme, addr = "https", url
+	}
 
-// ////////////////////////////////////////////////////////////////////////////////// //
+	switch strings.ToLower(scheme) {
+	case "https":
+		addr, format, _ := strings.Cut(addr, "#")
 
-// Resolve returns info about given domain
-func (r *Resolver) Resolve(domain string) (*Answer, error) {
-	resp, err := req.Request{
-		URL:    "https://" + r.URL,
-		Accept: "application/dns-json",
-		Query:  req.Query{"name": domain},
-	}.Get()
+		switch strings.ToLower(format) {
+		case DOH_AUTO, DOH_JSON, DOH_GET, DOH_POST:
+			return &DoHResolver{URL: addr, Format: strings.ToLower(format)}, nil
+		}
 
-	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("Unsupported DoH request format %q", format)
+
+	case "udp", "tcp":
+		addr, err := normalizeAddr(addr, "53")
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &NetResolver{Addr: addr, TCP: strings.EqualFold(scheme, "tcp")}, nil
+
+	case "tls":
+		addr, serverName, _ := strings.Cut(addr, "#")
+		addr, err := normalizeAddr(addr, "853")
+
+		if err != nil {
+			return nil, err
+		}
+
+		return &TLSResolver{Addr: addr, ServerName: serverName}, nil
 	}
 
-	if resp.StatusCode != 200 {
-		errInfo := &resolveError{}
-		err = resp.JSON(errInfo)
+	return nil, fmt.Errorf("Unsupported resolver scheme %q", scheme)
+}
 
-		if err == nil {
-			return nil, fmt.Errorf("Resolving error: %s", errInfo.Error)
+// LookupNS returns names of authoritative servers of given zone
+func LookupNS(resolver Resolver, zone string) []string {
+	answer, err := resolver.Resolve(zone, TYPE_NS)
+
+	if err != nil || answer.Status != STATUS_NOERROR {
+		return nil
+	}
+
+	var result []string
+
+	zone = strings.ToLower(strings.TrimRight(zone, "."))
+
+	for _, r := range answer.Records {
+		// Answer can contain CNAME records, so we must check record owner
+		if r.Type == TYPE_NS && strings.ToLower(strings.TrimRight(r.Name, ".")) == zone {
+			result = append(result, strings.ToLower(strings.TrimRight(r.NS(), ".")))
 		}
+	}
+
+	return result
+}
+
+// LookupAddrs returns IPv4 and IPv6 addresses of given host
+func LookupAddrs(resolver Resolver, host string) []string {
+	var result []string
 
-		return nil, fmt.Errorf("Resolver returned non-ok status code %d", resp.StatusCode)
+	answer, err := resolver.Resolve(host, TYPE_A)
+
+	if err == nil {
+		result = append(result, answer.IP()...)
+	}
+
+	answer, err = resolver.Resolve(host, TYPE_AAAA)
+
+	if err == nil {
+		result = append(result, answer.IPv6()...)
 	}
 
-	answer := &Answer{}
-	err = resp.JSON(answer)
+	return result
+}
+
+// ReverseName returns name for reverse lookup (in-addr.arpa or ip6.arpa) of
+// given IP address
+func ReverseName(ip string) (string, error) {
+	addr, err := netip.ParseAddr(ip)
 
 	if err != nil {
-		return nil, fmt.Errorf("Can't decode response: %v", err)
+		return "", fmt.Errorf("Invalid IP address %q", ip)
 	}
 
-	return answer, nil
+	addr = addr.Unmap()
+	data := addr.AsSlice()
+
+	var result strings.Builder
+
+	for i := len(data) - 1; i >= 0; i-- {
+		if addr.Is4() {
+			fmt.Fprintf(&result, "%d.", data[i])
+		} else {
+			fmt.Fprintf(&result, "%x.%x.", data[i]&0xF, data[i]>>4)
+		}
+	}
+
+	if addr.Is4() {
+		return result.String() + "in-addr.arpa", nil
+	}
+
+	return result.String() + "ip6.arpa", nil
 }
 
 // ////////////////////////////////////////////////////////////////////////////////// //
@@ -97,7 +229,7 @@ func (a *Answer) ToString(simple bool) string {
 	var result string
 
 	for _, r := range a.Records {
-		if r.Type == 5 { // 5 == CNAME
+		if r.Type == TYPE_CNAME {
 			if !simple {
 				result += r.Data + " → "
 			}
@@ -128,10 +260,101 @@ func (a *Answer) IP() []string {
 	var result []string
 
 	for _, r := range a.Records {
-		if r.Type == 1 { // 1 == A
+		if r.Type == TYPE_A {
 			result = append(result, r.Data)
 		}
 	}
 
 	return result
 }
+
+// IPv6 returns only AAAA records
+func (a *Answer) IPv6() []string {
+	if a == nil || a.Status != 0 || len(a.Records) == 0 {
+		return nil
+	}
+
+	var result []string
+
+	for _, r := range a.Records {
+		if r.Type == TYPE_AAAA {
+			result = append(result, r.Data)
+		}
+	}
+
+	return result
+}
+
+// Merge returns new answer with records from both answers. Records which present
+// in both answers (e.g. CNAME chain) are added only once.
+func (a *Answer) Merge(answer *Answer) *Answer {
+	switch {
+	case a == nil:
+		return answer
+	case answer == nil:
+		return a
+	}
+
+	result := &Answer{
+		Status:    a.Status,
+		TC:        a.TC || answer.TC,
+		RD:        a.RD,
+		RA:        a.RA,
+		AD:        a.AD && answer.AD,
+		CD:        a.CD,
+		Question:  slices.Concat(a.Question, answer.Question),
+		Authority: slices.Concat(a.Authority, answer.Authority),
+	}
+
+	if a.Status != STATUS_NOERROR {
+		result.Status = answer.Status
+	}
+
+	result.Records = append(result.Records, a.Records...)
+
+	for _, r := range answer.Records {
+		if !result.Records.Has(r) {
+			result.Records = append(result.Records, r)
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Has returns true if slice contains record with the same name, type and data
+func (r Records) Has(record *Record) bool {
+	for _, rr := range r {
+		if rr.Type == record.Type && rr.Data == record.Data &&
+			strings.EqualFold(rr.Name, record.Name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// normalizeAddr validates server address and adds default port if required
+func normalizeAddr(addr, defaultPort string) (string, error) {
+	addr = strings.TrimRight(addr, "/")
+
+	if addr == "" {
+		return "", fmt.Errorf("Resolver address is empty")
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+
+	if err != nil {
+		// Address without port (IPv6 address can be in brackets)
+		host, port = strings.Trim(addr, "[]"), defaultPort
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("Invalid resolver address %q", addr)
+	}
+
+	return net.JoinHostPort(host, port), nil
+}
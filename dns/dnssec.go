@@ -0,0 +1,111 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NSEC3_ALG_SHA1 is NSEC3 hash algorithm SHA-1
+const NSEC3_ALG_SHA1 = 1
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// CompareNames compares domain names using canonical DNS names order (RFC 4034).
+// Result is 0 if a == b, -1 if a < b, and +1 if a > b.
+func CompareNames(a, b string) int {
+	aLabels, _ := splitName(a)
+	bLabels, _ := splitName(b)
+
+	for i, j := len(aLabels)-1, len(bLabels)-1; i >= 0 || j >= 0; i, j = i-1, j-1 {
+		switch {
+		case i < 0:
+			return -1
+		case j < 0:
+			return 1
+		}
+
+		result := bytes.Compare(bytes.ToLower(aLabels[i]), bytes.ToLower(bLabels[j]))
+
+		if result != 0 {
+			return result
+		}
+	}
+
+	return 0
+}
+
+// CoversName returns true if NSEC record of zone with given owner and next name
+// covers given name
+func CoversName(owner, next, name, zone string) bool {
+	if CompareNames(owner, name) >= 0 {
+		return false
+	}
+
+	// The last NSEC record in the zone points to zone apex
+	return next == zone || CompareNames(name, next) < 0
+}
+
+// CoversHash returns true if NSEC3 record with given owner and next hashes covers
+// given hash. Hashes must be in the same case.
+func CoversHash(owner, next, hash string) bool {
+	if owner < next {
+		return owner < hash && hash < next
+	}
+
+	// The last record in the chain points to the first one
+	return hash > owner || hash < next
+}
+
+// CanonicalName returns domain name in canonical wire format (RFC 4034): without
+// compression and in lower case
+func CanonicalName(name string) ([]byte, error) {
+	labels, err := splitName(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var result []byte
+
+	for _, label := range labels {
+		result = append(result, byte(len(label)))
+		result = append(result, bytes.ToLower(label)...)
+	}
+
+	return append(result, 0), nil
+}
+
+// HashName returns NSEC3 hash (RFC 5155) of given name in base32hex encoding.
+// Salt must be encoded using hex.
+func HashName(name, salt string, iterations int) (string, error) {
+	data, err := CanonicalName(name)
+
+	if err != nil {
+		return "", err
+	}
+
+	saltData, err := hex.DecodeString(salt)
+
+	if err != nil {
+		return "", fmt.Errorf("Invalid NSEC3 salt: %w", err)
+	}
+
+	hash := sha1.Sum(append(data, saltData...))
+
+	for range iterations {
+		hash = sha1.Sum(append(hash[:], saltData...))
+	}
+
+	return base32HexEncoding.EncodeToString(hash[:]), nil
+}
@@ -0,0 +1,217 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"sync"
+
+	"github.com/essentialkaos/ek/v13/req"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DoH request formats
+const (
+	DOH_AUTO = ""     // Detect format automatically
+	DOH_JSON = "json" // JSON API (application/dns-json)
+	DOH_GET  = "get"  // RFC 8484 GET request (application/dns-message)
+	DOH_POST = "post" // RFC 8484 POST request (application/dns-message)
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+const (
+	CONTENT_TYPE_DNS_JSON    = "application/dns-json"
+	CONTENT_TYPE_DNS_MESSAGE = "application/dns-message"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DoHResolver is DNS-over-HTTPS resolver
+type DoHResolver struct {
+	URL    string // Resolver URL without scheme
+	Format string // Request format (default: auto)
+
+	detected string
+	mu       sync.RWMutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// resolveError is resolving error
+type resolveError struct {
+	Error string `json:"error"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Resolve returns info about given domain
+func (r *DoHResolver) Resolve(domain string, qtype int) (*Answer, error) {
+	switch r.getFormat() {
+	case DOH_JSON:
+		return r.resolveJSON(domain, qtype)
+	case DOH_GET, DOH_POST:
+		return r.resolveWire(domain, qtype, r.getFormat())
+	}
+
+	// RFC 8484 is standard, so we try it first and then fallback to JSON API
+	answer, err := r.resolveWire(domain, qtype, DOH_GET)
+
+	if err == nil {
+		r.setFormat(DOH_GET)
+		return answer, nil
+	}
+
+	answer, jsonErr := r.resolveJSON(domain, qtype)
+
+	if jsonErr != nil {
+		return nil, err
+	}
+
+	r.setFormat(DOH_JSON)
+
+	return answer, nil
+}
+
+// Exchange sends query to resolver using RFC 8484 wire format and returns response
+func (r *DoHResolver) Exchange(query *Message) (*Message, error) {
+	switch r.getFormat() {
+	case DOH_JSON:
+		return nil, fmt.Errorf("Resolver %s doesn't support wire format", r.URL)
+	case DOH_POST:
+		return r.exchangeWire(query, DOH_POST)
+	}
+
+	return r.exchangeWire(query, DOH_GET)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// resolveJSON resolves domain using JSON API
+func (r *DoHResolver) resolveJSON(domain string, qtype int) (*Answer, error) {
+	resp, err := req.Request{
+		URL:    "https://" + r.URL,
+		Accept: CONTENT_TYPE_DNS_JSON,
+		Query:  req.Query{"name": domain, "type": qtype},
+	}.Get()
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		errInfo := &resolveError{}
+		err = resp.JSON(errInfo)
+
+		if err == nil {
+			return nil, fmt.Errorf("Resolving error: %s", errInfo.Error)
+		}
+
+		return nil, fmt.Errorf("Resolver returned non-ok status code %d", resp.StatusCode)
+	}
+
+	answer := &Answer{}
+	err = resp.JSON(answer)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode response: %v", err)
+	}
+
+	return answer, nil
+}
+
+// resolveWire resolves domain using RFC 8484 wire format
+func (r *DoHResolver) resolveWire(domain string, qtype int, format string) (*Answer, error) {
+	msg, err := r.exchangeWire(NewQuery(domain, qtype), format)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return msg.ToAnswer(), nil
+}
+
+// exchangeWire sends query using RFC 8484 wire format
+func (r *DoHResolver) exchangeWire(query *Message, format string) (*Message, error) {
+	query.ID = 0 // RFC 8484 recommends to use 0 as ID for better caching
+
+	data, err := query.Pack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	rr := req.Request{
+		URL:         "https://" + r.URL,
+		Accept:      CONTENT_TYPE_DNS_MESSAGE,
+		AutoDiscard: true,
+	}
+
+	var resp *req.Response
+
+	if format == DOH_POST {
+		rr.Body, rr.ContentType = data, CONTENT_TYPE_DNS_MESSAGE
+		resp, err = rr.Post()
+	} else {
+		rr.Query = req.Query{"dns": base64.RawURLEncoding.EncodeToString(data)}
+		resp, err = rr.Get()
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Resolver returned non-ok status code %d", resp.StatusCode)
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+
+	if contentType != CONTENT_TYPE_DNS_MESSAGE {
+		resp.Discard()
+		return nil, fmt.Errorf("Resolver returned response with unsupported content type %q", contentType)
+	}
+
+	msg, err := Unpack(resp.Bytes())
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode response: %v", err)
+	}
+
+	if !msg.IsResponseTo(query) {
+		return nil, fmt.Errorf("Resolver returned response to another query")
+	}
+
+	return msg, nil
+}
+
+// getFormat returns request format
+func (r *DoHResolver) getFormat() string {
+	if r.Format != DOH_AUTO {
+		return r.Format
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.detected
+}
+
+// setFormat sets detected request format
+func (r *DoHResolver) setFormat(format string) {
+	if r.Format != DOH_AUTO {
+		return
+	}
+
+	r.mu.Lock()
+	r.detected = format
+	r.mu.Unlock()
+}
@@ -0,0 +1,69 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"sync"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// LimitedResolver is resolver wrapper which limits number of queries per second
+type LimitedResolver struct {
+	Resolver Resolver
+
+	interval time.Duration
+	next     time.Time
+	mu       sync.Mutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Limit returns resolver which sends no more than given number of queries per
+// second using given resolver. If limit is less than 1, resolver is returned as is.
+func Limit(resolver Resolver, qps int) Resolver {
+	if qps < 1 {
+		return resolver
+	}
+
+	return &LimitedResolver{
+		Resolver: resolver,
+		interval: time.Second / time.Duration(qps),
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Resolve returns info about given domain
+func (r *LimitedResolver) Resolve(domain string, qtype int) (*Answer, error) {
+	r.wait()
+	return r.Resolver.Resolve(domain, qtype)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// wait blocks until query can be sent
+func (r *LimitedResolver) wait() {
+	r.mu.Lock()
+
+	now := time.Now()
+
+	if r.next.Before(now) {
+		r.next = now
+	}
+
+	delay := r.next.Sub(now)
+	r.next = r.next.Add(r.interval)
+
+	r.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
@@ -0,0 +1,718 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Message header flags
+const (
+	FLAG_QR = 1 << 15 // Response
+	FLAG_AA = 1 << 10 // Authoritative answer
+	FLAG_TC = 1 << 9  // Truncated
+	FLAG_RD = 1 << 8  // Recursion desired
+	FLAG_RA = 1 << 7  // Recursion available
+	FLAG_AD = 1 << 5  // Authentic data
+	FLAG_CD = 1 << 4  // Checking disabled
+)
+
+// EDNS_FLAG_DO is EDNS0 flag for requesting DNSSEC records (RFC 3225)
+const EDNS_FLAG_DO = 1 << 15
+
+// CLASS_INET is Internet class
+const CLASS_INET = 1
+
+// EDNS_BUFFER_SIZE is advertised UDP payload size
+const EDNS_BUFFER_SIZE = 1232
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Message is DNS message in wire format (RFC 1035)
+type Message struct {
+	ID         uint16
+	Flags      uint16
+	Question   []*Question
+	Answer     Records
+	Authority  Records
+	Additional Records
+	DNSSEC     bool // Request DNSSEC records (DO bit)
+}
+
+// Question is DNS question
+type Question struct {
+	Name  string `json:"name"`
+	Type  int    `json:"type"`
+	Class int    `json:"-"`
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	errShortMessage = errors.New("DNS message is too short")
+	errBadPointer   = errors.New("DNS message contains invalid name compression pointer")
+	errBadName      = errors.New("Invalid domain name")
+)
+
+// base32HexEncoding is encoding used for NSEC3 hashes (RFC 5155)
+var base32HexEncoding = base32.HexEncoding.WithPadding(base32.NoPadding)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NewQuery creates new recursive query with EDNS0 support for given name and
+// record type. AD flag is set to get DNSSEC validation status from resolver
+// (RFC 6840).
+func NewQuery(name string, qtype int) *Message {
+	return &Message{
+		ID:       uint16(rand.UintN(65536)),
+		Flags:    FLAG_RD | FLAG_AD,
+		Question: []*Question{{Name: name, Type: qtype, Class: CLASS_INET}},
+	}
+}
+
+// Unpack parses DNS message in wire format
+func Unpack(data []byte) (*Message, error) {
+	if len(data) < 12 {
+		return nil, errShortMessage
+	}
+
+	m := &Message{
+		ID:    binary.BigEndian.Uint16(data[0:]),
+		Flags: binary.BigEndian.Uint16(data[2:]),
+	}
+
+	qdCount := int(binary.BigEndian.Uint16(data[4:]))
+	anCount := int(binary.BigEndian.Uint16(data[6:]))
+	nsCount := int(binary.BigEndian.Uint16(data[8:]))
+	arCount := int(binary.BigEndian.Uint16(data[10:]))
+
+	var err error
+
+	off := 12
+
+	for range qdCount {
+		q := &Question{}
+		q.Name, off, err = readName(data, off)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if off+4 > len(data) {
+			return nil, errShortMessage
+		}
+
+		q.Type = int(binary.BigEndian.Uint16(data[off:]))
+		q.Class = int(binary.BigEndian.Uint16(data[off+2:]))
+		m.Question = append(m.Question, q)
+		off += 4
+	}
+
+	m.Answer, off, err = readRecords(data, off, anCount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.Authority, off, err = readRecords(data, off, nsCount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	m.Additional, _, err = readRecords(data, off, arCount)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Pack encodes message header and question section to wire format. EDNS0 OPT
+// record is always added to additional section.
+func (m *Message) Pack() ([]byte, error) {
+	data := make([]byte, 12, 512)
+
+	binary.BigEndian.PutUint16(data[0:], m.ID)
+	binary.BigEndian.PutUint16(data[2:], m.Flags)
+	binary.BigEndian.PutUint16(data[4:], uint16(len(m.Question)))
+	binary.BigEndian.PutUint16(data[10:], 1)
+
+	var err error
+
+	for _, q := range m.Question {
+		data, err = appendName(data, q.Name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		data = binary.BigEndian.AppendUint16(data, uint16(q.Type))
+		data = binary.BigEndian.AppendUint16(data, uint16(q.Class))
+	}
+
+	var ednsFlags uint32
+
+	if m.DNSSEC {
+		ednsFlags |= EDNS_FLAG_DO
+	}
+
+	// OPT pseudo-record (RFC 6891)
+	data = append(data, 0)
+	data = binary.BigEndian.AppendUint16(data, TYPE_OPT)
+	data = binary.BigEndian.AppendUint16(data, EDNS_BUFFER_SIZE)
+	data = binary.BigEndian.AppendUint32(data, ednsFlags)
+	data = binary.BigEndian.AppendUint16(data, 0)
+
+	return data, nil
+}
+
+// IsResponseTo returns true if message is a response to given query
+func (m *Message) IsResponseTo(query *Message) bool {
+	if m.ID != query.ID || m.Flags&FLAG_QR == 0 {
+		return false
+	}
+
+	// Some servers don't copy question to responses with errors
+	if len(m.Question) == 0 {
+		return true
+	}
+
+	return len(query.Question) != 0 &&
+		m.Question[0].Type == query.Question[0].Type &&
+		strings.EqualFold(
+			strings.TrimRight(m.Question[0].Name, "."),
+			strings.TrimRight(query.Question[0].Name, "."),
+		)
+}
+
+// ToAnswer converts message to resolver answer
+func (m *Message) ToAnswer() *Answer {
+	return &Answer{
+		Status:    int(m.Flags & 0xF),
+		TC:        m.Flags&FLAG_TC != 0,
+		RD:        m.Flags&FLAG_RD != 0,
+		RA:        m.Flags&FLAG_RA != 0,
+		AD:        m.Flags&FLAG_AD != 0,
+		CD:        m.Flags&FLAG_CD != 0,
+		Question:  m.Question,
+		Records:   m.Answer,
+		Authority: m.Authority,
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// appendName appends domain name in wire format to given slice
+func appendName(data []byte, name string) ([]byte, error) {
+	labels, err := splitName(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, label := range labels {
+		data = append(data, byte(len(label)))
+		data = append(data, label...)
+	}
+
+	return append(data, 0), nil
+}
+
+// splitName splits domain name in presentation format to raw labels decoding
+// escaped characters (\. and \DDD)
+func splitName(name string) ([][]byte, error) {
+	var label []byte
+	var result [][]byte
+
+	if name == "" || name == "." {
+		return nil, nil
+	}
+
+	size := 1
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		switch {
+		case c == '.':
+			if len(label) == 0 || len(label) > 63 {
+				return nil, errBadName
+			}
+
+			size += len(label) + 1
+			result, label = append(result, label), nil
+
+			continue
+
+		case c == '\\' && i+3 < len(name) && isDigits(name[i+1:i+4]):
+			num, _ := strconv.Atoi(name[i+1 : i+4])
+
+			if num > 255 {
+				return nil, errBadName
+			}
+
+			c, i = byte(num), i+3
+
+		case c == '\\' && i+1 < len(name):
+			c, i = name[i+1], i+1
+
+		case c == '\\':
+			return nil, errBadName
+		}
+
+		label = append(label, c)
+	}
+
+	if len(label) != 0 {
+		if len(label) > 63 {
+			return nil, errBadName
+		}
+
+		size += len(label) + 1
+		result = append(result, label)
+	}
+
+	if size > 255 {
+		return nil, errBadName
+	}
+
+	return result, nil
+}
+
+// isDigits returns true if string contains only digits
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+// readName reads domain name with compression support from message. Name is
+// returned in fully qualified form (with trailing dot).
+func readName(data []byte, off int) (string, int, error) {
+	var name strings.Builder
+
+	end, jumps := -1, 0
+
+	for {
+		if off >= len(data) {
+			return "", 0, errShortMessage
+		}
+
+		size := int(data[off])
+
+		switch size & 0xC0 {
+		case 0x00:
+			off++
+
+			if size == 0 {
+				if end < 0 {
+					end = off
+				}
+
+				if name.Len() == 0 {
+					return ".", end, nil
+				}
+
+				return name.String(), end, nil
+			}
+
+			if off+size > len(data) {
+				return "", 0, errShortMessage
+			}
+
+			writeLabel(&name, data[off:off+size])
+			name.WriteByte('.')
+			off += size
+
+			if name.Len() > 255*4 {
+				return "", 0, errBadName
+			}
+
+		case 0xC0:
+			if off+2 > len(data) {
+				return "", 0, errShortMessage
+			}
+
+			if end < 0 {
+				end = off + 2
+			}
+
+			jumps++
+
+			if jumps > 64 {
+				return "", 0, errBadPointer
+			}
+
+			off = int(binary.BigEndian.Uint16(data[off:]) & 0x3FFF)
+
+		default:
+			return "", 0, errBadName
+		}
+	}
+}
+
+// canonicalRData returns record data in canonical form (RFC 4034, section 6.2)
+// with uncompressed domain names
+func canonicalRData(data []byte, off, size, rtype int) ([]byte, error) {
+	var prefix, names int
+
+	switch rtype {
+	case TYPE_NS, TYPE_CNAME, TYPE_PTR, TYPE_DNAME:
+		names = 1
+	case TYPE_MX:
+		prefix, names = 2, 1
+	case TYPE_SRV:
+		prefix, names = 6, 1
+	case TYPE_SOA:
+		names = 2
+	case TYPE_RRSIG:
+		prefix, names = 18, 1
+	default:
+		return slices.Clone(data[off : off+size]), nil
+	}
+
+	if prefix > size {
+		return nil, errShortMessage
+	}
+
+	result := slices.Clone(data[off : off+prefix])
+	next := off + prefix
+
+	for range names {
+		name, end, err := readName(data, next)
+
+		if err != nil {
+			return nil, err
+		}
+
+		wire, err := CanonicalName(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		result = append(result, wire...)
+		next = end
+	}
+
+	if next > off+size {
+		return nil, errShortMessage
+	}
+
+	return append(result, data[next:off+size]...), nil
+}
+
+// formatTime formats DNSSEC signature time (RFC 4034, section 3.2)
+func formatTime(t uint32) string {
+	return time.Unix(int64(t), 0).UTC().Format("20060102150405")
+}
+
+// formatTypeBitmap formats NSEC/NSEC3 type bitmap (RFC 4034) to list of type names
+func formatTypeBitmap(bitmap []byte) string {
+	var result []string
+
+	for len(bitmap) >= 2 {
+		window, size := int(bitmap[0]), int(bitmap[1])
+
+		if size > 32 || 2+size > len(bitmap) {
+			break
+		}
+
+		for i, b := range bitmap[2 : 2+size] {
+			for bit := range 8 {
+				if b&(0x80>>bit) != 0 {
+					result = append(result, TypeName(window*256+i*8+bit))
+				}
+			}
+		}
+
+		bitmap = bitmap[2+size:]
+	}
+
+	return strings.Join(result, " ")
+}
+
+// joinData joins record data fields skipping empty fields
+func joinData(fields ...string) string {
+	return strings.Join(slices.DeleteFunc(fields, func(f string) bool { return f == "" }), " ")
+}
+
+// writeLabel writes label to builder escaping special characters
+func writeLabel(b *strings.Builder, label []byte) {
+	for _, c := range label {
+		switch {
+		case c == '.' || c == '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case c < 0x21 || c > 0x7E:
+			fmt.Fprintf(b, "\\%03d", c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+// readRecords reads given number of resource records from message
+func readRecords(data []byte, off, count int) (Records, int, error) {
+	var err error
+	var result Records
+
+	for range count {
+		r := &Record{}
+		r.Name, off, err = readName(data, off)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if off+10 > len(data) {
+			return nil, 0, errShortMessage
+		}
+
+		r.Type = int(binary.BigEndian.Uint16(data[off:]))
+		r.TTL = int(binary.BigEndian.Uint32(data[off+4:]))
+		size := int(binary.BigEndian.Uint16(data[off+8:]))
+		off += 10
+
+		if off+size > len(data) {
+			return nil, 0, errShortMessage
+		}
+
+		if r.Type == TYPE_OPT {
+			off += size
+			continue
+		}
+
+		r.Data, err = formatRData(data, off, size, r.Type)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		r.rdata, err = canonicalRData(data, off, size, r.Type)
+
+		if err != nil {
+			return nil, 0, err
+		}
+
+		result = append(result, r)
+		off += size
+	}
+
+	return result, off, nil
+}
+
+// formatRData formats record data to presentation format used by DoH JSON API
+func formatRData(data []byte, off, size, rtype int) (string, error) {
+	rdata := data[off : off+size]
+
+	switch rtype {
+	case TYPE_A, TYPE_AAAA:
+		addr, ok := netip.AddrFromSlice(rdata)
+
+		if !ok || (rtype == TYPE_A) != addr.Is4() {
+			return "", fmt.Errorf("Invalid address in record with type %d", rtype)
+		}
+
+		return addr.String(), nil
+
+	case TYPE_NS, TYPE_CNAME, TYPE_PTR, TYPE_DNAME:
+		name, _, err := readName(data, off)
+		return name, err
+
+	case TYPE_MX:
+		if size < 3 {
+			return "", errShortMessage
+		}
+
+		name, _, err := readName(data, off+2)
+
+		return strconv.Itoa(int(binary.BigEndian.Uint16(rdata))) + " " + name, err
+
+	case TYPE_SRV:
+		if size < 7 {
+			return "", errShortMessage
+		}
+
+		name, _, err := readName(data, off+6)
+
+		return fmt.Sprintf(
+			"%d %d %d %s",
+			binary.BigEndian.Uint16(rdata),
+			binary.BigEndian.Uint16(rdata[2:]),
+			binary.BigEndian.Uint16(rdata[4:]),
+			name,
+		), err
+
+	case TYPE_SOA:
+		mname, next, err := readName(data, off)
+
+		if err != nil {
+			return "", err
+		}
+
+		rname, next, err := readName(data, next)
+
+		if err != nil {
+			return "", err
+		}
+
+		if next+20 > off+size {
+			return "", errShortMessage
+		}
+
+		return fmt.Sprintf(
+			"%s %s %d %d %d %d %d", mname, rname,
+			binary.BigEndian.Uint32(data[next:]),
+			binary.BigEndian.Uint32(data[next+4:]),
+			binary.BigEndian.Uint32(data[next+8:]),
+			binary.BigEndian.Uint32(data[next+12:]),
+			binary.BigEndian.Uint32(data[next+16:]),
+		), nil
+
+	case TYPE_TXT:
+		var result []string
+
+		for i := 0; i < size; {
+			l := int(rdata[i])
+
+			if i+1+l > size {
+				return "", errShortMessage
+			}
+
+			result = append(result, strconv.Quote(string(rdata[i+1:i+1+l])))
+			i += 1 + l
+		}
+
+		return strings.Join(result, " "), nil
+
+	case TYPE_NSEC:
+		next, end, err := readName(data, off)
+
+		if err != nil {
+			return "", err
+		}
+
+		if end > off+size {
+			return "", errShortMessage
+		}
+
+		return joinData(next, formatTypeBitmap(data[end:off+size])), nil
+
+	case TYPE_NSEC3, TYPE_NSEC3PARAM:
+		if size < 5 || 5+int(rdata[4]) > size {
+			return "", errShortMessage
+		}
+
+		saltEnd := 5 + int(rdata[4])
+		salt := strings.ToUpper(hex.EncodeToString(rdata[5:saltEnd]))
+
+		if salt == "" {
+			salt = "-"
+		}
+
+		params := fmt.Sprintf(
+			"%d %d %d %s", rdata[0], rdata[1],
+			binary.BigEndian.Uint16(rdata[2:]), salt,
+		)
+
+		if rtype == TYPE_NSEC3PARAM {
+			return params, nil
+		}
+
+		if saltEnd >= size || saltEnd+1+int(rdata[saltEnd]) > size {
+			return "", errShortMessage
+		}
+
+		hashEnd := saltEnd + 1 + int(rdata[saltEnd])
+		next := base32HexEncoding.EncodeToString(rdata[saltEnd+1 : hashEnd])
+
+		return joinData(params+" "+next, formatTypeBitmap(rdata[hashEnd:])), nil
+
+	case TYPE_DS:
+		if size < 4 {
+			return "", errShortMessage
+		}
+
+		return fmt.Sprintf(
+			"%d %d %d %s", binary.BigEndian.Uint16(rdata), rdata[2], rdata[3],
+			strings.ToUpper(hex.EncodeToString(rdata[4:])),
+		), nil
+
+	case TYPE_DNSKEY:
+		if size < 4 {
+			return "", errShortMessage
+		}
+
+		return fmt.Sprintf(
+			"%d %d %d %s", binary.BigEndian.Uint16(rdata), rdata[2], rdata[3],
+			base64.StdEncoding.EncodeToString(rdata[4:]),
+		), nil
+
+	case TYPE_RRSIG:
+		if size < 19 {
+			return "", errShortMessage
+		}
+
+		signer, end, err := readName(data, off+18)
+
+		if err != nil {
+			return "", err
+		}
+
+		if end > off+size {
+			return "", errShortMessage
+		}
+
+		return fmt.Sprintf(
+			"%s %d %d %d %s %s %d %s %s",
+			TypeName(int(binary.BigEndian.Uint16(rdata))), rdata[2], rdata[3],
+			binary.BigEndian.Uint32(rdata[4:]),
+			formatTime(binary.BigEndian.Uint32(rdata[8:])),
+			formatTime(binary.BigEndian.Uint32(rdata[12:])),
+			binary.BigEndian.Uint16(rdata[16:]), signer,
+			base64.StdEncoding.EncodeToString(data[end:off+size]),
+		), nil
+
+	case TYPE_CAA:
+		if size < 2 || 2+int(rdata[1]) > size {
+			return "", errShortMessage
+		}
+
+		tagEnd := 2 + int(rdata[1])
+
+		return fmt.Sprintf(
+			"%d %s %s", rdata[0], rdata[2:tagEnd],
+			strconv.Quote(string(rdata[tagEnd:])),
+		), nil
+	}
+
+	// Unknown record type (RFC 3597)
+	return fmt.Sprintf("\\# %d %s", size, hex.EncodeToString(rdata)), nil
+}
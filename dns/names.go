@@ -0,0 +1,41 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"math/rand/v2"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// labelChars is a set of characters used for random labels
+const labelChars = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Normalize returns domain name in lower case without trailing dot. Root zone
+// name is returned as is.
+func Normalize(name string) string {
+	if name == "." {
+		return name
+	}
+
+	return strings.ToLower(strings.TrimRight(name, "."))
+}
+
+// RandomLabel generates random label which most likely doesn't exist
+func RandomLabel() string {
+	label := make([]byte, 16)
+
+	for i := range label {
+		label[i] = labelChars[rand.IntN(len(labelChars))]
+	}
+
+	return string(label)
+}
@@ -0,0 +1,174 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_TIMEOUT is default query timeout
+const DEFAULT_TIMEOUT = 3 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NetResolver is plain DNS resolver which uses UDP with fallback to TCP for
+// truncated responses
+type NetResolver struct {
+	Addr    string        // Server address (host:port)
+	TCP     bool          // Use only TCP
+	Timeout time.Duration // Query timeout (default: 3s)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Resolve returns info about given domain
+func (r *NetResolver) Resolve(domain string, qtype int) (*Answer, error) {
+	resp, err := r.Exchange(NewQuery(domain, qtype))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.ToAnswer(), nil
+}
+
+// Exchange sends query to server and returns response
+func (r *NetResolver) Exchange(query *Message) (*Message, error) {
+	timeout := r.Timeout
+
+	if timeout <= 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+
+	if !r.TCP {
+		resp, err := exchangeUDP(r.Addr, query, timeout)
+
+		if err != nil || resp.Flags&FLAG_TC == 0 {
+			return resp, err
+		}
+	}
+
+	return exchangeTCP(r.Addr, query, timeout)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// exchangeUDP sends query over UDP
+func exchangeUDP(addr string, query *Message, timeout time.Duration) (*Message, error) {
+	data, err := query.Pack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("udp", addr, timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't connect to %s: %w", addr, err)
+	}
+
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	_, err = conn.Write(data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't send query to %s: %w", addr, err)
+	}
+
+	buf := make([]byte, 65535)
+
+	for {
+		n, err := conn.Read(buf)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't read response from %s: %w", addr, err)
+		}
+
+		resp, err := Unpack(buf[:n])
+
+		// Ignore malformed and unrelated datagrams
+		if err != nil || !resp.IsResponseTo(query) {
+			continue
+		}
+
+		return resp, nil
+	}
+}
+
+// exchangeTCP sends query over TCP
+func exchangeTCP(addr string, query *Message, timeout time.Duration) (*Message, error) {
+	data, err := query.Pack()
+
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't connect to %s: %w", addr, err)
+	}
+
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	err = writeStreamMessage(conn, data)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't send query to %s: %w", addr, err)
+	}
+
+	data, err = readStreamMessage(conn)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read response from %s: %w", addr, err)
+	}
+
+	resp, err := Unpack(data)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if !resp.IsResponseTo(query) {
+		return nil, fmt.Errorf("Server %s returned response to another query", addr)
+	}
+
+	return resp, nil
+}
+
+// writeStreamMessage writes message with 2-byte length prefix to stream
+func writeStreamMessage(w io.Writer, data []byte) error {
+	_, err := w.Write(append(binary.BigEndian.AppendUint16(nil, uint16(len(data))), data...))
+	return err
+}
+
+// readStreamMessage reads message with 2-byte length prefix from stream
+func readStreamMessage(r io.Reader) ([]byte, error) {
+	var size [2]byte
+
+	_, err := io.ReadFull(r, size[:])
+
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, binary.BigEndian.Uint16(size[:]))
+	_, err = io.ReadFull(r, data)
+
+	return data, err
+}
@@ -0,0 +1,222 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_MAX_FAILURES is default number of consecutive failures after which
+// resolver is ejected from pool
+const DEFAULT_MAX_FAILURES = 3
+
+// DEFAULT_EJECT_TIME is default time for which unhealthy resolver is ejected
+const DEFAULT_EJECT_TIME = 30 * time.Second
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// errEmptyPool is returned if pool doesn't contain resolvers
+var errEmptyPool = errors.New("Pool doesn't contain resolvers")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Pool is resolver which distributes queries between resolvers using round-robin.
+// Failed queries (errors, SERVFAIL and REFUSED answers) are retried using other
+// resolvers, and resolvers with consecutive failures are temporarily ejected.
+type Pool struct {
+	MaxFailures int           // Consecutive failures before ejection (default: 3)
+	EjectTime   time.Duration // Ejection time (default: 30s)
+
+	members []*poolMember
+	next    int
+	mu      sync.Mutex
+}
+
+// Health contains resolver health info
+type Health struct {
+	Name      string // Resolver name
+	Queries   int    // Number of sent queries
+	Failures  int    // Number of failed queries
+	Ejections int    // Number of ejections
+	Ejected   bool   // Resolver is ejected at the moment
+}
+
+// poolMember is resolver in pool
+type poolMember struct {
+	name     string
+	resolver Resolver
+
+	queries      int
+	failures     int
+	ejections    int
+	consecutive  int
+	ejectedUntil time.Time
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Add adds resolver with given name to pool
+func (p *Pool) Add(name string, resolver Resolver) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.members = append(p.members, &poolMember{name: name, resolver: resolver})
+}
+
+// Resolvers returns all resolvers from pool
+func (p *Pool) Resolvers() []Resolver {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var result []Resolver
+
+	for _, m := range p.members {
+		result = append(result, m.resolver)
+	}
+
+	return result
+}
+
+// Health returns health info of all resolvers from pool
+func (p *Pool) Health() []*Health {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var result []*Health
+
+	now := time.Now()
+
+	for _, m := range p.members {
+		result = append(result, &Health{
+			Name:      m.name,
+			Queries:   m.queries,
+			Failures:  m.failures,
+			Ejections: m.ejections,
+			Ejected:   now.Before(m.ejectedUntil),
+		})
+	}
+
+	return result
+}
+
+// Resolve returns info about given domain
+func (p *Pool) Resolve(domain string, qtype int) (*Answer, error) {
+	var answer *Answer
+	var err error
+
+	members := p.pick()
+
+	if len(members) == 0 {
+		return nil, errEmptyPool
+	}
+
+	for _, m := range members {
+		answer, err = m.resolver.Resolve(domain, qtype)
+		failed := isFailedAnswer(answer, err)
+
+		p.record(m, failed)
+
+		if !failed {
+			return answer, nil
+		}
+	}
+
+	return answer, err
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// pick returns resolvers in order in which they must be used for the next query.
+// Ejected resolvers are used only if all other resolvers failed.
+func (p *Pool) pick() []*poolMember {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var healthy, ejected []*poolMember
+
+	now := time.Now()
+	next := p.next
+
+	for i := range p.members {
+		index := (p.next + i) % len(p.members)
+		m := p.members[index]
+
+		if now.Before(m.ejectedUntil) {
+			ejected = append(ejected, m)
+			continue
+		}
+
+		// The next query starts from resolver after the first healthy one, so
+		// queries of ejected resolvers are distributed evenly
+		if len(healthy) == 0 {
+			next = index + 1
+		}
+
+		healthy = append(healthy, m)
+	}
+
+	if len(p.members) != 0 {
+		p.next = next % len(p.members)
+	}
+
+	return append(healthy, ejected...)
+}
+
+// record records query result for given resolver
+func (p *Pool) record(m *poolMember, failed bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	m.queries++
+
+	if !failed {
+		m.consecutive = 0
+		return
+	}
+
+	m.failures++
+	m.consecutive++
+
+	if m.consecutive < p.getMaxFailures() {
+		return
+	}
+
+	m.consecutive = 0
+	m.ejections++
+	m.ejectedUntil = time.Now().Add(p.getEjectTime())
+}
+
+// getMaxFailures returns number of consecutive failures before ejection
+func (p *Pool) getMaxFailures() int {
+	if p.MaxFailures <= 0 {
+		return DEFAULT_MAX_FAILURES
+	}
+
+	return p.MaxFailures
+}
+
+// getEjectTime returns ejection time
+func (p *Pool) getEjectTime() time.Duration {
+	if p.EjectTime <= 0 {
+		return DEFAULT_EJECT_TIME
+	}
+
+	return p.EjectTime
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// isFailedAnswer returns true if query must be retried using another resolver
+func isFailedAnswer(answer *Answer, err error) bool {
+	return err != nil || answer == nil ||
+		answer.Status == STATUS_SERVFAIL || answer.Status == STATUS_REFUSED
+}
@@ -0,0 +1,353 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// MX is typed view of MX record
+type MX struct {
+	Preference int
+	Exchange   string
+}
+
+// SOA is typed view of SOA record
+type SOA struct {
+	MName   string
+	RName   string
+	Serial  uint32
+	Refresh uint32
+	Retry   uint32
+	Expire  uint32
+	Minimum uint32
+}
+
+// SRV is typed view of SRV record
+type SRV struct {
+	Priority int
+	Weight   int
+	Port     int
+	Target   string
+}
+
+// CAA is typed view of CAA record
+type CAA struct {
+	Flags int
+	Tag   string
+	Value string
+}
+
+// NSEC is typed view of NSEC record
+type NSEC struct {
+	Next  string
+	Types []int
+}
+
+// NSEC3 is typed view of NSEC3 record
+type NSEC3 struct {
+	Algorithm  int
+	Flags      int
+	Iterations int
+	Salt       string
+	Next       string
+	Types      []int
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// typeNames contains names of supported record types
+var typeNames = map[int]string{
+	TYPE_A:          "A",
+	TYPE_NS:         "NS",
+	TYPE_CNAME:      "CNAME",
+	TYPE_SOA:        "SOA",
+	TYPE_PTR:        "PTR",
+	TYPE_MX:         "MX",
+	TYPE_TXT:        "TXT",
+	TYPE_AAAA:       "AAAA",
+	TYPE_SRV:        "SRV",
+	TYPE_DNAME:      "DNAME",
+	TYPE_DS:         "DS",
+	TYPE_RRSIG:      "RRSIG",
+	TYPE_NSEC:       "NSEC",
+	TYPE_DNSKEY:     "DNSKEY",
+	TYPE_NSEC3:      "NSEC3",
+	TYPE_NSEC3PARAM: "NSEC3PARAM",
+	TYPE_CAA:        "CAA",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// TypeName returns name of record type
+func TypeName(rtype int) string {
+	name, ok := typeNames[rtype]
+
+	if !ok {
+		return "TYPE" + strconv.Itoa(rtype)
+	}
+
+	return name
+}
+
+// ParseType parses record type name
+func ParseType(name string) (int, bool) {
+	name = strings.ToUpper(strings.TrimSpace(name))
+
+	for rtype, typeName := range typeNames {
+		if typeName == name {
+			return rtype, true
+		}
+	}
+
+	if strings.HasPrefix(name, "TYPE") {
+		rtype, err := strconv.Atoi(name[4:])
+		return rtype, err == nil && rtype > 0 && rtype < 65536
+	}
+
+	return 0, false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// RData returns record data in canonical wire format (RFC 4034). Data is available
+// only for records from wire-format responses.
+func (r *Record) RData() []byte {
+	if r == nil {
+		return nil
+	}
+
+	return r.rdata
+}
+
+// NS returns name server from NS record
+func (r *Record) NS() string {
+	if r == nil || r.Type != TYPE_NS {
+		return ""
+	}
+
+	return r.Data
+}
+
+// MX returns typed view of MX record
+func (r *Record) MX() *MX {
+	if r == nil || r.Type != TYPE_MX {
+		return nil
+	}
+
+	fields := strings.Fields(r.Data)
+
+	if len(fields) != 2 {
+		return nil
+	}
+
+	pref, err := strconv.Atoi(fields[0])
+
+	if err != nil {
+		return nil
+	}
+
+	return &MX{Preference: pref, Exchange: fields[1]}
+}
+
+// TXT returns strings from TXT record
+func (r *Record) TXT() []string {
+	if r == nil || r.Type != TYPE_TXT {
+		return nil
+	}
+
+	// Some DoH JSON providers return TXT data without quotes
+	if !strings.HasPrefix(r.Data, `"`) {
+		return []string{r.Data}
+	}
+
+	var result []string
+
+	for data := strings.TrimSpace(r.Data); data != ""; data = strings.TrimSpace(data) {
+		str, err := strconv.QuotedPrefix(data)
+
+		if err != nil {
+			return append(result, data)
+		}
+
+		value, err := strconv.Unquote(str)
+
+		if err != nil {
+			value = strings.Trim(str, `"`)
+		}
+
+		result = append(result, value)
+		data = data[len(str):]
+	}
+
+	return result
+}
+
+// SOA returns typed view of SOA record
+func (r *Record) SOA() *SOA {
+	if r == nil || r.Type != TYPE_SOA {
+		return nil
+	}
+
+	fields := strings.Fields(r.Data)
+
+	if len(fields) != 7 {
+		return nil
+	}
+
+	var nums [5]uint32
+
+	for i, field := range fields[2:] {
+		num, err := strconv.ParseUint(field, 10, 32)
+
+		if err != nil {
+			return nil
+		}
+
+		nums[i] = uint32(num)
+	}
+
+	return &SOA{
+		MName:   fields[0],
+		RName:   fields[1],
+		Serial:  nums[0],
+		Refresh: nums[1],
+		Retry:   nums[2],
+		Expire:  nums[3],
+		Minimum: nums[4],
+	}
+}
+
+// SRV returns typed view of SRV record
+func (r *Record) SRV() *SRV {
+	if r == nil || r.Type != TYPE_SRV {
+		return nil
+	}
+
+	fields := strings.Fields(r.Data)
+
+	if len(fields) != 4 {
+		return nil
+	}
+
+	var nums [3]int
+
+	for i, field := range fields[:3] {
+		num, err := strconv.Atoi(field)
+
+		if err != nil {
+			return nil
+		}
+
+		nums[i] = num
+	}
+
+	return &SRV{Priority: nums[0], Weight: nums[1], Port: nums[2], Target: fields[3]}
+}
+
+// CAA returns typed view of CAA record
+func (r *Record) CAA() *CAA {
+	if r == nil || r.Type != TYPE_CAA {
+		return nil
+	}
+
+	fields := strings.SplitN(r.Data, " ", 3)
+
+	if len(fields) != 3 {
+		return nil
+	}
+
+	flags, err := strconv.Atoi(fields[0])
+
+	if err != nil {
+		return nil
+	}
+
+	value, err := strconv.Unquote(fields[2])
+
+	if err != nil {
+		value = fields[2]
+	}
+
+	return &CAA{Flags: flags, Tag: fields[1], Value: value}
+}
+
+// NSEC returns typed view of NSEC record
+func (r *Record) NSEC() *NSEC {
+	if r == nil || r.Type != TYPE_NSEC {
+		return nil
+	}
+
+	fields := strings.Fields(r.Data)
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &NSEC{Next: fields[0], Types: parseTypes(fields[1:])}
+}
+
+// NSEC3 returns typed view of NSEC3 record
+func (r *Record) NSEC3() *NSEC3 {
+	if r == nil || r.Type != TYPE_NSEC3 {
+		return nil
+	}
+
+	fields := strings.Fields(r.Data)
+
+	if len(fields) < 5 {
+		return nil
+	}
+
+	var nums [3]int
+
+	for i, field := range fields[:3] {
+		num, err := strconv.Atoi(field)
+
+		if err != nil {
+			return nil
+		}
+
+		nums[i] = num
+	}
+
+	salt := fields[3]
+
+	if salt == "-" {
+		salt = ""
+	}
+
+	return &NSEC3{
+		Algorithm:  nums[0],
+		Flags:      nums[1],
+		Iterations: nums[2],
+		Salt:       salt,
+		Next:       fields[4],
+		Types:      parseTypes(fields[5:]),
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseTypes parses list of record type names
+func parseTypes(names []string) []int {
+	var result []int
+
+	for _, name := range names {
+		rtype, ok := ParseType(name)
+
+		if ok {
+			result = append(result, rtype)
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,282 @@
+package dns
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// TLSResolver is DNS-over-TLS resolver (RFC 7858). Resolver uses one TLS session
+// for all queries and sends queries without waiting for responses (pipelining).
+type TLSResolver struct {
+	Addr       string        // Server address (host:port)
+	ServerName string        // Server name for certificate verification (optional)
+	Timeout    time.Duration // Query timeout (default: 3s)
+	TLSConfig  *tls.Config   // Custom TLS configuration (optional)
+
+	conn    *tls.Conn
+	pending map[uint16]chan *Message
+	mu      sync.Mutex
+	writeMu sync.Mutex
+	dialMu  sync.Mutex
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// errConnClosed is returned if connection was closed before response was received
+var errConnClosed = errors.New("Connection closed by server")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Resolve returns info about given domain
+func (r *TLSResolver) Resolve(domain string, qtype int) (*Answer, error) {
+	resp, err := r.Exchange(NewQuery(domain, qtype))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return resp.ToAnswer(), nil
+}
+
+// Exchange sends query to server and returns response
+func (r *TLSResolver) Exchange(query *Message) (*Message, error) {
+	resp, err := r.exchange(query)
+
+	// Server can close idle connection at any time, so we retry query
+	// once using new connection
+	if errors.Is(err, errConnClosed) {
+		resp, err = r.exchange(query)
+	}
+
+	return resp, err
+}
+
+// Close closes connection to server
+func (r *TLSResolver) Close() error {
+	conn := r.getConn()
+
+	if conn == nil {
+		return nil
+	}
+
+	r.dropConn(conn)
+
+	return nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// exchange sends query using shared connection and waits for response
+func (r *TLSResolver) exchange(query *Message) (*Message, error) {
+	timeout := r.Timeout
+
+	if timeout <= 0 {
+		timeout = DEFAULT_TIMEOUT
+	}
+
+	conn, respChan, err := r.register(query, timeout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := query.Pack()
+
+	if err != nil {
+		r.unregister(query.ID)
+		return nil, err
+	}
+
+	r.writeMu.Lock()
+	conn.SetWriteDeadline(time.Now().Add(timeout))
+	err = writeStreamMessage(conn, data)
+	r.writeMu.Unlock()
+
+	if err != nil {
+		r.dropConn(conn)
+		return nil, fmt.Errorf("Can't send query to %s: %w", r.Addr, errConnClosed)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case resp := <-respChan:
+		if resp == nil {
+			return nil, fmt.Errorf("Can't read response from %s: %w", r.Addr, errConnClosed)
+		}
+
+		if !resp.IsResponseTo(query) {
+			return nil, fmt.Errorf("Server %s returned response to another query", r.Addr)
+		}
+
+		return resp, nil
+
+	case <-timer.C:
+		r.unregister(query.ID)
+		return nil, fmt.Errorf("Server %s didn't respond in time", r.Addr)
+	}
+}
+
+// register connects to server if required and registers query as pending. Query
+// ID is changed if it's already used by another pending query.
+func (r *TLSResolver) register(query *Message, timeout time.Duration) (*tls.Conn, chan *Message, error) {
+	conn, err := r.connect(timeout)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Connection can be dropped while query is waiting for lock
+	if r.conn != conn {
+		return nil, nil, fmt.Errorf("Can't send query to %s: %w", r.Addr, errConnClosed)
+	}
+
+	for r.pending[query.ID] != nil {
+		query.ID++
+	}
+
+	respChan := make(chan *Message, 1)
+	r.pending[query.ID] = respChan
+
+	return r.conn, respChan, nil
+}
+
+// unregister removes pending query
+func (r *TLSResolver) unregister(id uint16) {
+	r.mu.Lock()
+	delete(r.pending, id)
+	r.mu.Unlock()
+}
+
+// connect returns shared connection to server. New connection is created if
+// there is no connection yet. Dial and TLS handshake are performed without
+// holding lock used by pending queries.
+func (r *TLSResolver) connect(timeout time.Duration) (*tls.Conn, error) {
+	conn := r.getConn()
+
+	if conn != nil {
+		return conn, nil
+	}
+
+	r.dialMu.Lock()
+	defer r.dialMu.Unlock()
+
+	// Connection can be created by another query while we were waiting for lock
+	conn = r.getConn()
+
+	if conn != nil {
+		return conn, nil
+	}
+
+	conn, err := r.dial(timeout)
+
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.conn, r.pending = conn, map[uint16]chan *Message{}
+	r.mu.Unlock()
+
+	go r.readLoop(conn)
+
+	return conn, nil
+}
+
+// getConn returns current connection to server
+func (r *TLSResolver) getConn() *tls.Conn {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.conn
+}
+
+// dial creates new TLS connection to server
+func (r *TLSResolver) dial(timeout time.Duration) (*tls.Conn, error) {
+	config := r.TLSConfig
+
+	if config == nil {
+		config = &tls.Config{}
+	}
+
+	config = config.Clone()
+
+	if config.ServerName == "" {
+		config.ServerName = r.ServerName
+	}
+
+	if config.ServerName == "" {
+		config.ServerName, _, _ = net.SplitHostPort(r.Addr)
+	}
+
+	conn, err := tls.DialWithDialer(&net.Dialer{Timeout: timeout}, "tcp", r.Addr, config)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't connect to %s: %w", r.Addr, err)
+	}
+
+	return conn, nil
+}
+
+// readLoop reads responses from connection and passes them to waiting queries
+func (r *TLSResolver) readLoop(conn *tls.Conn) {
+	for {
+		data, err := readStreamMessage(conn)
+
+		if err != nil {
+			r.dropConn(conn)
+			return
+		}
+
+		resp, err := Unpack(data)
+
+		if err != nil {
+			continue
+		}
+
+		r.mu.Lock()
+		respChan := r.pending[resp.ID]
+		delete(r.pending, resp.ID)
+		r.mu.Unlock()
+
+		if respChan != nil {
+			respChan <- resp
+		}
+	}
+}
+
+// dropConn closes connection and fails all queries waiting for responses
+func (r *TLSResolver) dropConn(conn *tls.Conn) {
+	conn.Close()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.conn != conn {
+		return
+	}
+
+	for id, respChan := range r.pending {
+		respChan <- nil
+		delete(r.pending, id)
+	}
+
+	r.conn, r.pending = nil, nil
+}
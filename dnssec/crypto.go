@@ -0,0 +1,428 @@
+package dnssec
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DNSSEC algorithms (RFC 8624)
+const (
+	ALG_RSASHA1         = 5
+	ALG_RSASHA1_NSEC3   = 7
+	ALG_RSASHA256       = 8
+	ALG_RSASHA512       = 10
+	ALG_ECDSAP256SHA256 = 13
+	ALG_ECDSAP384SHA384 = 14
+	ALG_ED25519         = 15
+)
+
+// DS digest types
+const (
+	DIGEST_SHA1   = 1
+	DIGEST_SHA256 = 2
+	DIGEST_SHA384 = 4
+)
+
+// DNSKEY_FLAG_ZONE is DNSKEY flag for zone keys
+const DNSKEY_FLAG_ZONE = 1 << 8
+
+// DNSKEY_PROTOCOL is the only valid DNSKEY protocol value
+const DNSKEY_PROTOCOL = 3
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// dnskey is parsed DNSKEY record
+type dnskey struct {
+	owner     string
+	flags     uint16
+	protocol  uint8
+	algorithm uint8
+	publicKey []byte
+	rdata     []byte
+	tag       uint16
+}
+
+// rrsig is parsed RRSIG record
+type rrsig struct {
+	typeCovered int
+	algorithm   uint8
+	labels      uint8
+	origTTL     uint32
+	expiration  uint32
+	inception   uint32
+	keyTag      uint16
+	signer      string
+	signature   []byte
+	header      []byte // RRSIG data without signature
+}
+
+// ds is parsed DS record
+type ds struct {
+	keyTag     uint16
+	algorithm  uint8
+	digestType uint8
+	digest     []byte
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+var (
+	errBadRecord            = errors.New("Malformed DNSSEC record")
+	errUnsupportedAlgorithm = errors.New("Unsupported DNSSEC algorithm")
+	errBadSignature         = errors.New("Signature verification failed")
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseDNSKEY parses DNSKEY record
+func parseDNSKEY(r *dns.Record) (*dnskey, error) {
+	rdata := r.RData()
+
+	if r.Type != dns.TYPE_DNSKEY || len(rdata) < 5 {
+		return nil, errBadRecord
+	}
+
+	return &dnskey{
+		owner:     dns.Normalize(r.Name),
+		flags:     binary.BigEndian.Uint16(rdata),
+		protocol:  rdata[2],
+		algorithm: rdata[3],
+		publicKey: rdata[4:],
+		rdata:     rdata,
+		tag:       keyTag(rdata),
+	}, nil
+}
+
+// parseRRSIG parses RRSIG record
+func parseRRSIG(r *dns.Record) (*rrsig, error) {
+	rdata := r.RData()
+
+	if r.Type != dns.TYPE_RRSIG || len(rdata) < 19 {
+		return nil, errBadRecord
+	}
+
+	signerEnd, err := skipName(rdata, 18)
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer, err := nameString(rdata[18:signerEnd])
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &rrsig{
+		typeCovered: int(binary.BigEndian.Uint16(rdata)),
+		algorithm:   rdata[2],
+		labels:      rdata[3],
+		origTTL:     binary.BigEndian.Uint32(rdata[4:]),
+		expiration:  binary.BigEndian.Uint32(rdata[8:]),
+		inception:   binary.BigEndian.Uint32(rdata[12:]),
+		keyTag:      binary.BigEndian.Uint16(rdata[16:]),
+		signer:      signer,
+		signature:   rdata[signerEnd:],
+		header:      rdata[:signerEnd],
+	}, nil
+}
+
+// parseDS parses DS record
+func parseDS(r *dns.Record) (*ds, error) {
+	rdata := r.RData()
+
+	if r.Type != dns.TYPE_DS || len(rdata) < 5 {
+		return nil, errBadRecord
+	}
+
+	return &ds{
+		keyTag:     binary.BigEndian.Uint16(rdata),
+		algorithm:  rdata[2],
+		digestType: rdata[3],
+		digest:     rdata[4:],
+	}, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// isValidAt returns true if signature is valid at given time. Signature time
+// uses serial number arithmetic (RFC 4034, section 3.1.5).
+func (s *rrsig) isValidAt(t time.Time) bool {
+	now := uint32(t.Unix())
+	return int32(now-s.inception) >= 0 && int32(s.expiration-now) >= 0
+}
+
+// verify verifies signature of RRset using given key
+func (s *rrsig) verify(key *dnskey, rrset dns.Records) error {
+	if key.algorithm != s.algorithm || key.tag != s.keyTag || key.owner != s.signer {
+		return errBadSignature
+	}
+
+	data, err := signedData(s, rrset)
+
+	if err != nil {
+		return err
+	}
+
+	switch s.algorithm {
+	case ALG_RSASHA1, ALG_RSASHA1_NSEC3:
+		return verifyRSA(key.publicKey, crypto.SHA1, data, s.signature)
+	case ALG_RSASHA256:
+		return verifyRSA(key.publicKey, crypto.SHA256, data, s.signature)
+	case ALG_RSASHA512:
+		return verifyRSA(key.publicKey, crypto.SHA512, data, s.signature)
+	case ALG_ECDSAP256SHA256:
+		return verifyECDSA(key.publicKey, elliptic.P256(), crypto.SHA256, data, s.signature)
+	case ALG_ECDSAP384SHA384:
+		return verifyECDSA(key.publicKey, elliptic.P384(), crypto.SHA384, data, s.signature)
+	case ALG_ED25519:
+		if len(key.publicKey) != ed25519.PublicKeySize || !ed25519.Verify(key.publicKey, data, s.signature) {
+			return errBadSignature
+		}
+
+		return nil
+	}
+
+	return errUnsupportedAlgorithm
+}
+
+// matches returns true if DS record matches given key
+func (d *ds) matches(key *dnskey) bool {
+	if d.keyTag != key.tag || d.algorithm != key.algorithm {
+		return false
+	}
+
+	owner, err := dns.CanonicalName(key.owner)
+
+	if err != nil {
+		return false
+	}
+
+	data := append(owner, key.rdata...)
+
+	switch d.digestType {
+	case DIGEST_SHA1:
+		digest := sha1.Sum(data)
+		return bytes.Equal(d.digest, digest[:])
+	case DIGEST_SHA256:
+		digest := sha256.Sum256(data)
+		return bytes.Equal(d.digest, digest[:])
+	case DIGEST_SHA384:
+		digest := sha512.Sum384(data)
+		return bytes.Equal(d.digest, digest[:])
+	}
+
+	return false
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// signedData returns data covered by signature (RFC 4034, section 3.1.8.1)
+func signedData(sig *rrsig, rrset dns.Records) ([]byte, error) {
+	if len(rrset) == 0 {
+		return nil, errBadRecord
+	}
+
+	owner, err := dns.CanonicalName(rrset[0].Name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	labels := countLabels(owner)
+
+	if labels < int(sig.labels) {
+		return nil, errBadSignature
+	}
+
+	// RRset expanded from wildcard is signed with wildcard owner name
+	if labels > int(sig.labels) {
+		for ; labels > int(sig.labels); labels-- {
+			owner = owner[int(owner[0])+1:]
+		}
+
+		owner = append([]byte{1, '*'}, owner...)
+	}
+
+	var rdatas [][]byte
+
+	for _, r := range rrset {
+		if r.RData() == nil {
+			return nil, errBadRecord
+		}
+
+		rdatas = append(rdatas, r.RData())
+	}
+
+	slices.SortFunc(rdatas, bytes.Compare)
+	rdatas = slices.CompactFunc(rdatas, bytes.Equal)
+
+	data := slices.Clone(sig.header)
+
+	for _, rdata := range rdatas {
+		data = append(data, owner...)
+		data = binary.BigEndian.AppendUint16(data, uint16(rrset[0].Type))
+		data = binary.BigEndian.AppendUint16(data, dns.CLASS_INET)
+		data = binary.BigEndian.AppendUint32(data, sig.origTTL)
+		data = binary.BigEndian.AppendUint16(data, uint16(len(rdata)))
+		data = append(data, rdata...)
+	}
+
+	return data, nil
+}
+
+// verifyRSA verifies RSA signature (RFC 3110)
+func verifyRSA(publicKey []byte, hash crypto.Hash, data, signature []byte) error {
+	if len(publicKey) < 3 {
+		return errBadRecord
+	}
+
+	expSize, off := int(publicKey[0]), 1
+
+	if expSize == 0 {
+		expSize, off = int(binary.BigEndian.Uint16(publicKey[1:])), 3
+	}
+
+	if expSize == 0 || expSize > 4 || off+expSize >= len(publicKey) {
+		return errBadRecord
+	}
+
+	var exp int
+
+	for _, b := range publicKey[off : off+expSize] {
+		exp = exp<<8 | int(b)
+	}
+
+	key := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(publicKey[off+expSize:]),
+		E: exp,
+	}
+
+	h := hash.New()
+	h.Write(data)
+
+	err := rsa.VerifyPKCS1v15(key, hash, h.Sum(nil), signature)
+
+	if err != nil {
+		return errBadSignature
+	}
+
+	return nil
+}
+
+// verifyECDSA verifies ECDSA signature (RFC 6605)
+func verifyECDSA(publicKey []byte, curve elliptic.Curve, hash crypto.Hash, data, signature []byte) error {
+	size := (curve.Params().BitSize + 7) / 8
+
+	if len(publicKey) != size*2 || len(signature) != size*2 {
+		return errBadRecord
+	}
+
+	key := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(publicKey[:size]),
+		Y:     new(big.Int).SetBytes(publicKey[size:]),
+	}
+
+	h := hash.New()
+	h.Write(data)
+
+	r := new(big.Int).SetBytes(signature[:size])
+	s := new(big.Int).SetBytes(signature[size:])
+
+	if !ecdsa.Verify(key, h.Sum(nil), r, s) {
+		return errBadSignature
+	}
+
+	return nil
+}
+
+// keyTag calculates key tag of DNSKEY record (RFC 4034, appendix B)
+func keyTag(rdata []byte) uint16 {
+	var ac uint32
+
+	for i, b := range rdata {
+		if i&1 == 0 {
+			ac += uint32(b) << 8
+		} else {
+			ac += uint32(b)
+		}
+	}
+
+	ac += ac >> 16 & 0xFFFF
+
+	return uint16(ac & 0xFFFF)
+}
+
+// countLabels returns number of labels in name in wire format
+func countLabels(name []byte) int {
+	var result int
+
+	for off := 0; off < len(name) && name[off] != 0; off += int(name[off]) + 1 {
+		result++
+	}
+
+	return result
+}
+
+// skipName returns offset of the first byte after uncompressed name
+func skipName(data []byte, off int) (int, error) {
+	for off < len(data) {
+		size := int(data[off])
+
+		if size == 0 {
+			return off + 1, nil
+		}
+
+		if size > 63 {
+			return 0, errBadRecord
+		}
+
+		off += size + 1
+	}
+
+	return 0, errBadRecord
+}
+
+// nameString converts uncompressed name in wire format to string
+func nameString(data []byte) (string, error) {
+	var result []byte
+
+	for off := 0; off < len(data) && data[off] != 0; off += int(data[off]) + 1 {
+		if off+int(data[off])+1 > len(data) {
+			return "", errBadRecord
+		}
+
+		result = fmt.Appendf(result, "%s.", data[off+1:off+int(data[off])+1])
+	}
+
+	if len(result) == 0 {
+		return ".", nil
+	}
+
+	return dns.Normalize(string(result)), nil
+}
@@ -0,0 +1,273 @@
+package dnssec
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// RSA/SHA-1 key from RFC 4034, section 5.4 (also used in RFC 4509, section 2.2.1)
+const rfc4034Key = "AQOeiiR0GOMYkDshWoSKz9XzfwJr1AYtsmx3TGkJaNXVbfi/2pHm822aJ5iI9BMzNXxe" +
+	"YCmZDRD99WYwYqUSdjMmmAphXdvxegXd/M5+X7OrzKBaMbCVdFLUUh6DhweJBjEVv5f2" +
+	"wwjM9XzcnOf+EPbtG9DMBmADjFDc2w/rljwvFw=="
+
+// Ed25519 key and signature from RFC 8080, section 6.1
+const (
+	rfc8080Key = "l02Woi0iS8Aa25FQkUd9RMzZHJpBoRQwAQEX1SxZJA4="
+	rfc8080Sig = "oL9krJun7xfBOIWcGHi7mag5/hdZrKWw15jPGrHpjQeRAvTdszaPD+QLs3fx8A4M3e23mRZ9VrbpMngwcrqNAg=="
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestKeyTag(t *testing.T) {
+	key := parseTestKey(t, "dskey.example.com", 256, ALG_RSASHA1, rfc4034Key)
+
+	if key.tag != 60485 {
+		t.Fatalf("Expected key tag 60485, got %d", key.tag)
+	}
+
+	key = parseTestKey(t, "example.com", 257, ALG_ED25519, rfc8080Key)
+
+	if key.tag != 3613 {
+		t.Fatalf("Expected key tag 3613, got %d", key.tag)
+	}
+}
+
+func TestDSMatches(t *testing.T) {
+	key := parseTestKey(t, "dskey.example.com", 256, ALG_RSASHA1, rfc4034Key)
+
+	dsSHA1 := testDS(60485, ALG_RSASHA1, DIGEST_SHA1, "2BB183AF5F22588179A53B0A98631FAD1A292118")
+	dsSHA256 := testDS(
+		60485, ALG_RSASHA1, DIGEST_SHA256,
+		"D4B7D520E7BB5F0F67674A0CCEB1E3E0614B93C4F9E99B8383F6A1E4469DA50A",
+	)
+
+	if !dsSHA1.matches(key) {
+		t.Fatal("SHA-1 DS record must match key (RFC 4034)")
+	}
+
+	if !dsSHA256.matches(key) {
+		t.Fatal("SHA-256 DS record must match key (RFC 4509)")
+	}
+
+	edKey := parseTestKey(t, "example.com", 257, ALG_ED25519, rfc8080Key)
+	edDS := testDS(
+		3613, ALG_ED25519, DIGEST_SHA256,
+		"3aa5ab37efce57f737fc1627013fee07bdf241bd10f3b1964ab55c78e79a304b",
+	)
+
+	if !edDS.matches(edKey) {
+		t.Fatal("SHA-256 DS record must match key (RFC 8080)")
+	}
+
+	if dsSHA1.matches(edKey) || edDS.matches(key) {
+		t.Fatal("DS record must not match key with another tag and algorithm")
+	}
+
+	otherOwner := parseTestKey(t, "other.example.com", 256, ALG_RSASHA1, rfc4034Key)
+
+	if dsSHA256.matches(otherOwner) {
+		t.Fatal("DS record must not match the same key of another zone")
+	}
+
+	badDigest := testDS(60485, ALG_RSASHA1, DIGEST_SHA1, "2BB183AF5F22588179A53B0A98631FAD1A292119")
+
+	if badDigest.matches(key) {
+		t.Fatal("DS record with wrong digest must not match key")
+	}
+}
+
+func TestSignatureVerify(t *testing.T) {
+	key := parseTestKey(t, "example.com", 257, ALG_ED25519, rfc8080Key)
+	mx := newTestRecord(t, "example.com", dns.TYPE_MX, mxRData(10, "mail.example.com"))
+	sig := newTestRRSIG(t, "example.com", dns.TYPE_MX, ALG_ED25519, 2, 1440021600, 1438207200, 3613, "example.com", rfc8080Sig)
+
+	err := sig.verify(key, dns.Records{mx})
+
+	if err != nil {
+		t.Fatalf("Valid RFC 8080 signature is rejected: %v", err)
+	}
+
+	mx = newTestRecord(t, "example.com", dns.TYPE_MX, mxRData(20, "mail.example.com"))
+
+	if sig.verify(key, dns.Records{mx}) == nil {
+		t.Fatal("Signature of modified RRset must be rejected")
+	}
+}
+
+func TestSignedData(t *testing.T) {
+	sig := &rrsig{labels: 2, origTTL: 3600, header: []byte{0xAA, 0xBB}}
+
+	a1 := newTestRecord(t, "www.example.com", dns.TYPE_A, []byte{10, 0, 0, 2})
+	a2 := newTestRecord(t, "WWW.Example.COM.", dns.TYPE_A, []byte{10, 0, 0, 1})
+	a3 := newTestRecord(t, "www.example.com", dns.TYPE_A, []byte{10, 0, 0, 1})
+
+	data, err := signedData(&rrsig{labels: 3, origTTL: 3600, header: []byte{0xAA, 0xBB}}, dns.Records{a1, a2, a3})
+
+	if err != nil {
+		t.Fatalf("Can't build signed data: %v", err)
+	}
+
+	// RDATA is sorted, duplicates are removed and owner is lowercased
+	expected := []byte{0xAA, 0xBB}
+	expected = appendTestRR(expected, "\x03www\x07example\x03com\x00", dns.TYPE_A, 3600, []byte{10, 0, 0, 1})
+	expected = appendTestRR(expected, "\x03www\x07example\x03com\x00", dns.TYPE_A, 3600, []byte{10, 0, 0, 2})
+
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("Invalid signed data:\n got %x\nwant %x", data, expected)
+	}
+
+	// RRset synthesized from *.example.com is signed with wildcard owner
+	wc := newTestRecord(t, "a.b.example.com", dns.TYPE_A, []byte{10, 0, 0, 1})
+	data, err = signedData(sig, dns.Records{wc})
+
+	if err != nil {
+		t.Fatalf("Can't build signed data: %v", err)
+	}
+
+	expected = appendTestRR([]byte{0xAA, 0xBB}, "\x01*\x07example\x03com\x00", dns.TYPE_A, 3600, []byte{10, 0, 0, 1})
+
+	if !bytes.Equal(data, expected) {
+		t.Fatalf("Invalid signed data for wildcard:\n got %x\nwant %x", data, expected)
+	}
+
+	// Signature can't have more labels than owner
+	_, err = signedData(&rrsig{labels: 4}, dns.Records{a1})
+
+	if err == nil {
+		t.Fatal("Signature with more labels than owner must be rejected")
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// parseTestKey returns parsed DNSKEY record
+func parseTestKey(t *testing.T, owner string, flags uint16, alg uint8, key string) *dnskey {
+	t.Helper()
+
+	publicKey, err := base64.StdEncoding.DecodeString(key)
+
+	if err != nil {
+		t.Fatalf("Can't decode key: %v", err)
+	}
+
+	rdata := binary.BigEndian.AppendUint16(nil, flags)
+	rdata = append(rdata, DNSKEY_PROTOCOL, alg)
+	rdata = append(rdata, publicKey...)
+
+	result, err := parseDNSKEY(newTestRecord(t, owner, dns.TYPE_DNSKEY, rdata))
+
+	if err != nil {
+		t.Fatalf("Can't parse DNSKEY: %v", err)
+	}
+
+	return result
+}
+
+// newTestRRSIG returns parsed RRSIG record
+func newTestRRSIG(t *testing.T, owner string, covered int, alg, labels uint8, expiration, inception uint32, tag uint16, signer, signature string) *rrsig {
+	t.Helper()
+
+	sigData, err := base64.StdEncoding.DecodeString(signature)
+
+	if err != nil {
+		t.Fatalf("Can't decode signature: %v", err)
+	}
+
+	signerData, _ := dns.CanonicalName(signer)
+
+	rdata := binary.BigEndian.AppendUint16(nil, uint16(covered))
+	rdata = append(rdata, alg, labels)
+	rdata = binary.BigEndian.AppendUint32(rdata, 3600)
+	rdata = binary.BigEndian.AppendUint32(rdata, expiration)
+	rdata = binary.BigEndian.AppendUint32(rdata, inception)
+	rdata = binary.BigEndian.AppendUint16(rdata, tag)
+	rdata = append(rdata, signerData...)
+	rdata = append(rdata, sigData...)
+
+	result, err := parseRRSIG(newTestRecord(t, owner, dns.TYPE_RRSIG, rdata))
+
+	if err != nil {
+		t.Fatalf("Can't parse RRSIG: %v", err)
+	}
+
+	return result
+}
+
+// testDS returns DS record with given fields
+func testDS(tag uint16, alg, digestType uint8, digest string) *ds {
+	data, _ := hex.DecodeString(digest)
+	return &ds{keyTag: tag, algorithm: alg, digestType: digestType, digest: data}
+}
+
+// mxRData returns MX record data in wire format
+func mxRData(pref uint16, exchange string) []byte {
+	name, _ := dns.CanonicalName(exchange)
+	return append(binary.BigEndian.AppendUint16(nil, pref), name...)
+}
+
+// appendTestRR appends record in canonical wire format to data
+func appendTestRR(data []byte, owner string, rtype int, ttl uint32, rdata []byte) []byte {
+	data = append(data, owner...)
+	data = binary.BigEndian.AppendUint16(data, uint16(rtype))
+	data = binary.BigEndian.AppendUint16(data, dns.CLASS_INET)
+	data = binary.BigEndian.AppendUint32(data, ttl)
+	data = binary.BigEndian.AppendUint16(data, uint16(len(rdata)))
+
+	return append(data, rdata...)
+}
+
+// newTestRecord returns record with given data unpacked from DNS message
+func newTestRecord(t *testing.T, name string, rtype int, rdata []byte) *dns.Record {
+	t.Helper()
+
+	owner, err := dns.CanonicalName(name)
+
+	if err != nil {
+		t.Fatalf("Invalid name %q: %v", name, err)
+	}
+
+	// Owner name case is kept to check canonicalization
+	if name != "." {
+		owner = wireName(name)
+	}
+
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg[2:], dns.FLAG_QR)
+	binary.BigEndian.PutUint16(msg[6:], 1)
+	msg = appendTestRR(msg, string(owner), rtype, 3600, rdata)
+
+	resp, err := dns.Unpack(msg)
+
+	if err != nil || len(resp.Answer) != 1 {
+		t.Fatalf("Can't unpack record %s: %v", name, err)
+	}
+
+	return resp.Answer[0]
+}
+
+// wireName converts name to wire format keeping labels case
+func wireName(name string) []byte {
+	var result []byte
+
+	for _, label := range bytes.Split([]byte(name), []byte(".")) {
+		if len(label) != 0 {
+			result = append(append(result, byte(len(label))), label...)
+		}
+	}
+
+	return append(result, 0)
+}
@@ -0,0 +1,313 @@
+package dnssec
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// NSEC3_FLAG_OPT_OUT is NSEC3 opt-out flag (RFC 5155, section 3.1.2.1)
+const NSEC3_FLAG_OPT_OUT = 1
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// denial contains NSEC/NSEC3 records of zone which prove non-existence of names
+// or records. Records must be validated before use.
+type denial struct {
+	zone  string
+	nsec  []*nsecRecord
+	nsec3 []*nsec3Record
+}
+
+// nsecRecord is NSEC record with owner name
+type nsecRecord struct {
+	owner string
+	*dns.NSEC
+}
+
+// nsec3Record is NSEC3 record with owner hash
+type nsec3Record struct {
+	hash string
+	*dns.NSEC3
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// newDenial creates denial proof from NSEC/NSEC3 records of given zone
+func newDenial(zone string, records dns.Records) *denial {
+	d := &denial{zone: zone}
+
+	for _, r := range records {
+		owner := dns.Normalize(r.Name)
+
+		if !isInZone(owner, zone) {
+			continue
+		}
+
+		if nsec := r.NSEC(); nsec != nil {
+			nsec.Next = dns.Normalize(nsec.Next)
+			d.nsec = append(d.nsec, &nsecRecord{owner, nsec})
+		}
+
+		hash, parent, _ := strings.Cut(owner, ".")
+
+		if parent == "" {
+			parent = "."
+		}
+
+		if nsec3 := r.NSEC3(); nsec3 != nil && parent == zone {
+			nsec3.Next = strings.ToUpper(nsec3.Next)
+			d.nsec3 = append(d.nsec3, &nsec3Record{strings.ToUpper(hash), nsec3})
+		}
+	}
+
+	return d
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// proveNXDomain checks that records prove non-existence of given name (RFC 4035,
+// section 5.4 and RFC 5155, section 8.4)
+func (d *denial) proveNXDomain(name string) error {
+	if len(d.nsec) != 0 {
+		covering := d.findCoveringNSEC(name)
+
+		if covering == nil {
+			return fmt.Errorf("There is no NSEC record covering %s", name)
+		}
+
+		ce := closestEncloser(name, covering)
+
+		if d.findCoveringNSEC("*."+ce) == nil {
+			return fmt.Errorf("There is no NSEC record proving absence of wildcard *.%s", ce)
+		}
+
+		return nil
+	}
+
+	ce, _, err := d.proveClosestEncloser(name)
+
+	if err != nil {
+		return err
+	}
+
+	_, err = d.findCoveringNSEC3("*." + ce)
+
+	if err != nil {
+		return fmt.Errorf("There is no NSEC3 record proving absence of wildcard *.%s", ce)
+	}
+
+	return nil
+}
+
+// proveNoData checks that records prove absence of records with given type for
+// given name (RFC 4035, section 5.4 and RFC 5155, sections 8.5 and 8.6). It
+// returns true if absence of DS records is proved by NSEC3 record with opt-out flag.
+func (d *denial) proveNoData(name string, qtype int) (bool, error) {
+	if len(d.nsec) != 0 {
+		return false, d.proveNoDataNSEC(name, qtype)
+	}
+
+	nsec3, err := d.findMatchingNSEC3(name)
+
+	if err == nil {
+		return false, checkTypes(name, qtype, nsec3.Types)
+	}
+
+	// Absence of DS records for insecure delegation can be proved by NSEC3 with
+	// opt-out flag covering next closer name (RFC 5155, section 8.6)
+	if qtype != dns.TYPE_DS {
+		return false, err
+	}
+
+	_, covering, err := d.proveClosestEncloser(name)
+
+	if err != nil {
+		return false, err
+	}
+
+	if covering.Flags&NSEC3_FLAG_OPT_OUT == 0 {
+		return false, fmt.Errorf("NSEC3 record covering %s doesn't have opt-out flag", name)
+	}
+
+	return true, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// proveNoDataNSEC checks that NSEC records prove absence of records with given
+// type for given name
+func (d *denial) proveNoDataNSEC(name string, qtype int) error {
+	for _, nsec := range d.nsec {
+		if nsec.owner == name {
+			return checkTypes(name, qtype, nsec.Types)
+		}
+	}
+
+	// Empty non-terminal name is covered by NSEC record which next name is
+	// subdomain of this name
+	covering := d.findCoveringNSEC(name)
+
+	if covering != nil && qtype != dns.TYPE_DS && strings.HasSuffix(covering.Next, "."+name) {
+		return nil
+	}
+
+	return fmt.Errorf("There is no NSEC record matching %s", name)
+}
+
+// proveClosestEncloser performs closest encloser proof for given name (RFC 5155,
+// section 8.3) and returns closest encloser with NSEC3 record covering next
+// closer name
+func (d *denial) proveClosestEncloser(name string) (string, *nsec3Record, error) {
+	if len(d.nsec3) == 0 {
+		return "", nil, fmt.Errorf("There are no NSEC/NSEC3 records of zone %s", d.zone)
+	}
+
+	if !isInZone(name, d.zone) {
+		return "", nil, fmt.Errorf("Name %s isn't in zone %s", name, d.zone)
+	}
+
+	nextCloser := ""
+
+	for candidate := name; ; candidate = parentName(candidate) {
+		_, err := d.findMatchingNSEC3(candidate)
+
+		if err == nil {
+			break
+		}
+
+		if candidate == d.zone {
+			return "", nil, fmt.Errorf("There is no NSEC3 record matching closest encloser of %s", name)
+		}
+
+		nextCloser = candidate
+	}
+
+	if nextCloser == "" {
+		return "", nil, fmt.Errorf("NSEC3 record matches %s", name)
+	}
+
+	covering, err := d.findCoveringNSEC3(nextCloser)
+
+	if err != nil {
+		return "", nil, err
+	}
+
+	return parentName(nextCloser), covering, nil
+}
+
+// findCoveringNSEC returns NSEC record which covers given name
+func (d *denial) findCoveringNSEC(name string) *nsecRecord {
+	for _, nsec := range d.nsec {
+		if dns.CoversName(nsec.owner, nsec.Next, name, d.zone) {
+			return nsec
+		}
+	}
+
+	return nil
+}
+
+// findMatchingNSEC3 returns NSEC3 record which owner is hash of given name
+func (d *denial) findMatchingNSEC3(name string) (*nsec3Record, error) {
+	hash, err := d.hashName(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nsec3 := range d.nsec3 {
+		if nsec3.hash == hash {
+			return nsec3, nil
+		}
+	}
+
+	return nil, fmt.Errorf("There is no NSEC3 record matching %s", name)
+}
+
+// findCoveringNSEC3 returns NSEC3 record which covers hash of given name
+func (d *denial) findCoveringNSEC3(name string) (*nsec3Record, error) {
+	hash, err := d.hashName(name)
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, nsec3 := range d.nsec3 {
+		if dns.CoversHash(nsec3.hash, nsec3.Next, hash) {
+			return nsec3, nil
+		}
+	}
+
+	return nil, fmt.Errorf("There is no NSEC3 record covering %s", name)
+}
+
+// hashName returns NSEC3 hash of name. All NSEC3 records of zone must use the
+// same parameters (RFC 5155, section 7.1).
+func (d *denial) hashName(name string) (string, error) {
+	if len(d.nsec3) == 0 {
+		return "", fmt.Errorf("There are no NSEC/NSEC3 records of zone %s", d.zone)
+	}
+
+	params := d.nsec3[0]
+
+	for _, nsec3 := range d.nsec3 {
+		if nsec3.Algorithm != dns.NSEC3_ALG_SHA1 {
+			return "", fmt.Errorf("Unsupported NSEC3 hash algorithm %d", nsec3.Algorithm)
+		}
+
+		if nsec3.Iterations != params.Iterations || !strings.EqualFold(nsec3.Salt, params.Salt) {
+			return "", fmt.Errorf("NSEC3 records of zone %s have different parameters", d.zone)
+		}
+	}
+
+	return dns.HashName(name, params.Salt, params.Iterations)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// checkTypes checks that types bitmap of record matching name proves absence of
+// records with given type
+func checkTypes(name string, qtype int, types []int) error {
+	switch {
+	case slices.Contains(types, qtype):
+		return fmt.Errorf("Denial record of %s contains %s type", name, dns.TypeName(qtype))
+	case slices.Contains(types, dns.TYPE_CNAME):
+		return fmt.Errorf("Denial record of %s contains CNAME type", name)
+	}
+
+	// Absence of DS records is proved only by record of delegation point from
+	// parent zone (RFC 4035, section 5.2 and RFC 6840, section 4.4)
+	if qtype == dns.TYPE_DS {
+		if !slices.Contains(types, dns.TYPE_NS) || slices.Contains(types, dns.TYPE_SOA) {
+			return fmt.Errorf("Denial record of %s isn't record of delegation point", name)
+		}
+	}
+
+	return nil
+}
+
+// closestEncloser returns the longest ancestor of name which is ancestor of owner
+// or next name of NSEC record covering name
+func closestEncloser(name string, nsec *nsecRecord) string {
+	for ce := parentName(name); ; ce = parentName(ce) {
+		if isInZone(nsec.owner, ce) || isInZone(nsec.Next, ce) || ce == "." {
+			return ce
+		}
+	}
+}
+
+// isInZone returns true if name is zone apex or subdomain of zone
+func isInZone(name, zone string) bool {
+	return zone == "." || name == zone || strings.HasSuffix(name, "."+zone)
+}
@@ -0,0 +1,257 @@
+package dnssec
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/base32"
+	"encoding/binary"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// testNSEC is NSEC record of example zone
+type testNSEC struct {
+	owner string
+	next  string
+	types []int
+}
+
+// testNSEC3 is NSEC3 record of example zone
+type testNSEC3 struct {
+	name  string
+	hash  string
+	types []int
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Signed zone from RFC 4035, Appendix A
+var rfc4035Zone = []testNSEC{
+	{"example", "a.example", []int{dns.TYPE_NS, dns.TYPE_SOA, dns.TYPE_MX, dns.TYPE_RRSIG, dns.TYPE_NSEC, dns.TYPE_DNSKEY}},
+	{"a.example", "ai.example", []int{dns.TYPE_NS, dns.TYPE_DS, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"ai.example", "b.example", []int{dns.TYPE_A, dns.TYPE_AAAA, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"b.example", "ns1.example", []int{dns.TYPE_NS, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"ns1.example", "ns2.example", []int{dns.TYPE_A, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"ns2.example", "*.w.example", []int{dns.TYPE_A, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"*.w.example", "x.w.example", []int{dns.TYPE_MX, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"x.w.example", "x.y.w.example", []int{dns.TYPE_MX, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"x.y.w.example", "xx.example", []int{dns.TYPE_MX, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+	{"xx.example", "example", []int{dns.TYPE_A, dns.TYPE_AAAA, dns.TYPE_RRSIG, dns.TYPE_NSEC}},
+}
+
+// Signed zone from RFC 5155, Appendix A (salt AABBCCDD, 12 iterations), sorted
+// by hash
+var rfc5155Zone = []testNSEC3{
+	{"example", "0p9mhaveqvm6t7vbl5lop2u3t2rp3tom", []int{dns.TYPE_NS, dns.TYPE_SOA, dns.TYPE_MX, dns.TYPE_RRSIG, dns.TYPE_DNSKEY, dns.TYPE_NSEC3PARAM}},
+	{"ns1.example", "2t7b4g4vsa5smi47k61mv5bv1a22bojr", []int{dns.TYPE_A, dns.TYPE_RRSIG}},
+	{"x.y.w.example", "2vptu5timamqttgl4luu9kg21e0aor3s", []int{dns.TYPE_MX, dns.TYPE_RRSIG}},
+	{"a.example", "35mthgpgcu1qg68fab165klnsnk3dpvl", []int{dns.TYPE_NS, dns.TYPE_DS, dns.TYPE_RRSIG}},
+	{"x.w.example", "b4um86eghhds6nea196smvmlo4ors995", []int{dns.TYPE_MX, dns.TYPE_RRSIG}},
+	{"ai.example", "gjeqe526plbf1g8mklp59enfd789njgi", []int{dns.TYPE_A, dns.TYPE_AAAA, dns.TYPE_RRSIG}},
+	{"y.w.example", "ji6neoaepv8b5o6k4ev33abha8ht9fgc", nil},
+	{"w.example", "k8udemvp1j2f7eg6jebps17vp3n8i58h", nil},
+	{"ns2.example", "q04jkcevqvmu85r014c7dkba38o0ji5r", []int{dns.TYPE_A, dns.TYPE_RRSIG}},
+	{"*.w.example", "r53bq7cc2uvmubfu5ocmm6pers9tk9en", []int{dns.TYPE_MX, dns.TYPE_RRSIG}},
+	{"xx.example", "t644ebqk9bibcna874givr6joj62mlhv", []int{dns.TYPE_A, dns.TYPE_AAAA, dns.TYPE_RRSIG}},
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestNSECProofs(t *testing.T) {
+	d := newTestNSECDenial(t, rfc4035Zone)
+
+	// RFC 4035, Appendix B.2
+	if err := d.proveNXDomain("ml.example"); err != nil {
+		t.Fatalf("Can't prove non-existence of ml.example: %v", err)
+	}
+
+	if d.proveNXDomain("ns1.example") == nil {
+		t.Fatal("Non-existence of existing name must not be proved")
+	}
+
+	// NSEC covering name without NSEC proving absence of wildcard
+	noWildcard := newTestNSECDenial(t, rfc4035Zone[3:4])
+
+	if noWildcard.proveNXDomain("ml.example") == nil {
+		t.Fatal("Non-existence must not be proved without wildcard proof")
+	}
+
+	// RFC 4035, Appendix B.3
+	if _, err := d.proveNoData("ns1.example", dns.TYPE_MX); err != nil {
+		t.Fatalf("Can't prove absence of MX records of ns1.example: %v", err)
+	}
+
+	if _, err := d.proveNoData("ns1.example", dns.TYPE_A); err == nil {
+		t.Fatal("Absence of existing A records must not be proved")
+	}
+
+	// Empty non-terminal
+	if _, err := d.proveNoData("y.w.example", dns.TYPE_A); err != nil {
+		t.Fatalf("Can't prove absence of records of empty non-terminal: %v", err)
+	}
+
+	// RFC 4035, Appendix B.7
+	if _, err := d.proveNoData("b.example", dns.TYPE_DS); err != nil {
+		t.Fatalf("Can't prove insecure delegation b.example: %v", err)
+	}
+
+	if _, err := d.proveNoData("a.example", dns.TYPE_DS); err == nil {
+		t.Fatal("Absence of existing DS records must not be proved")
+	}
+
+	// Absence of DS can't be proved by zone apex NSEC
+	if _, err := d.proveNoData("example", dns.TYPE_DS); err == nil {
+		t.Fatal("Absence of DS records must not be proved by apex NSEC")
+	}
+}
+
+func TestNSEC3Hash(t *testing.T) {
+	for _, r := range rfc5155Zone {
+		hash, err := dns.HashName(r.name, "AABBCCDD", 12)
+
+		if err != nil {
+			t.Fatalf("Can't hash %s: %v", r.name, err)
+		}
+
+		if hash != strings.ToUpper(r.hash) {
+			t.Fatalf("Invalid hash of %s: expected %s, got %s", r.name, strings.ToUpper(r.hash), hash)
+		}
+	}
+}
+
+func TestNSEC3Proofs(t *testing.T) {
+	d := newTestNSEC3Denial(t, rfc5155Zone, 0)
+
+	// RFC 5155, Appendix B.1
+	if err := d.proveNXDomain("a.c.x.w.example"); err != nil {
+		t.Fatalf("Can't prove non-existence of a.c.x.w.example: %v", err)
+	}
+
+	if d.proveNXDomain("x.w.example") == nil {
+		t.Fatal("Non-existence of existing name must not be proved")
+	}
+
+	// Remove record covering wildcard of closest encloser
+	wildcard, err := d.findCoveringNSEC3("*.x.w.example")
+
+	if err != nil {
+		t.Fatalf("Can't find NSEC3 record covering wildcard: %v", err)
+	}
+
+	noWildcard := newTestNSEC3Denial(t, slices.DeleteFunc(slices.Clone(rfc5155Zone), func(r testNSEC3) bool {
+		return strings.EqualFold(r.hash, wildcard.hash)
+	}), 0)
+
+	if noWildcard.proveNXDomain("a.c.x.w.example") == nil {
+		t.Fatal("Non-existence must not be proved without wildcard proof")
+	}
+
+	// RFC 5155, Appendix B.2
+	if _, err := d.proveNoData("ns1.example", dns.TYPE_MX); err != nil {
+		t.Fatalf("Can't prove absence of MX records of ns1.example: %v", err)
+	}
+
+	if _, err := d.proveNoData("ns1.example", dns.TYPE_A); err == nil {
+		t.Fatal("Absence of existing A records must not be proved")
+	}
+
+	// RFC 5155, Appendix B.2.1
+	if _, err := d.proveNoData("y.w.example", dns.TYPE_A); err != nil {
+		t.Fatalf("Can't prove absence of records of empty non-terminal: %v", err)
+	}
+
+	if _, err := d.proveNoData("a.example", dns.TYPE_DS); err == nil {
+		t.Fatal("Absence of existing DS records must not be proved")
+	}
+
+	// RFC 5155, Appendix B.3
+	if _, err := d.proveNoData("c.example", dns.TYPE_DS); err == nil {
+		t.Fatal("Absence of DS records must not be proved without opt-out flag")
+	}
+
+	optOut := newTestNSEC3Denial(t, rfc5155Zone, NSEC3_FLAG_OPT_OUT)
+	isOptOut, err := optOut.proveNoData("c.example", dns.TYPE_DS)
+
+	if err != nil || !isOptOut {
+		t.Fatalf("Can't prove opt-out delegation c.example: %v", err)
+	}
+
+	if _, err := optOut.proveNoData("c.example", dns.TYPE_A); err == nil {
+		t.Fatal("Opt-out must prove only absence of DS records")
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// newTestNSECDenial returns denial proof with given NSEC records of example zone
+func newTestNSECDenial(t *testing.T, zone []testNSEC) *denial {
+	t.Helper()
+
+	var records dns.Records
+
+	for _, r := range zone {
+		rdata := append(wireName(r.next), typeBitmap(r.types)...)
+		records = append(records, newTestRecord(t, r.owner, dns.TYPE_NSEC, rdata))
+	}
+
+	return newDenial("example", records)
+}
+
+// newTestNSEC3Denial returns denial proof with given NSEC3 records of example zone
+func newTestNSEC3Denial(t *testing.T, zone []testNSEC3, flags uint8) *denial {
+	t.Helper()
+
+	var records dns.Records
+
+	for i, r := range zone {
+		next, err := base32.HexEncoding.DecodeString(strings.ToUpper(rfc5155Zone[nextNSEC3(zone, i)].hash))
+
+		if err != nil {
+			t.Fatalf("Can't decode hash: %v", err)
+		}
+
+		rdata := []byte{dns.NSEC3_ALG_SHA1, flags}
+		rdata = binary.BigEndian.AppendUint16(rdata, 12)
+		rdata = append(rdata, 4, 0xAA, 0xBB, 0xCC, 0xDD, byte(len(next)))
+		rdata = append(rdata, next...)
+		rdata = append(rdata, typeBitmap(r.types)...)
+
+		records = append(records, newTestRecord(t, r.hash+".example", dns.TYPE_NSEC3, rdata))
+	}
+
+	return newDenial("example", records)
+}
+
+// nextNSEC3 returns index of next hash in full zone for record with given index
+func nextNSEC3(zone []testNSEC3, index int) int {
+	full := slices.IndexFunc(rfc5155Zone, func(r testNSEC3) bool {
+		return r.hash == zone[index].hash
+	})
+
+	return (full + 1) % len(rfc5155Zone)
+}
+
+// typeBitmap returns NSEC/NSEC3 types bitmap for types from window 0
+func typeBitmap(types []int) []byte {
+	if len(types) == 0 {
+		return nil
+	}
+
+	bitmap := make([]byte, slices.Max(types)/8+1)
+
+	for _, t := range types {
+		bitmap[t/8] |= 0x80 >> (t % 8)
+	}
+
+	return append([]byte{0, byte(len(bitmap))}, bitmap...)
+}
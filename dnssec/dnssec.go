@@ -0,0 +1,558 @@
+package dnssec
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validation statuses (RFC 4035, section 4.3)
+const (
+	STATUS_SECURE        = "secure"
+	STATUS_INSECURE      = "insecure"
+	STATUS_BOGUS         = "bogus"
+	STATUS_INDETERMINATE = "indeterminate"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validator performs local DNSSEC chain-of-trust validation from root zone. Results
+// for zones are cached, so validator isn't safe for concurrent use.
+type Validator struct {
+	Exchanger dns.Exchanger // Wire-format resolver which returns DNSSEC records
+	Anchors   []string      // Root trust anchors in DS presentation format (default: IANA root KSKs)
+
+	zones map[string]*Zone // Zone name → validation info
+	cuts  map[string]string
+}
+
+// Zone contains zone validation info
+type Zone struct {
+	Name   string // Zone name
+	Status string // Validation status
+	Error  error  // Validation error for bogus and indeterminate zones
+
+	keys []*dnskey
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// RootAnchors contains DS records of IANA root zone KSKs
+var RootAnchors = []string{
+	"20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	"38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Validate validates answer for given name and record type. It returns validation
+// status and error which explains why answer is bogus or indeterminate.
+func (v *Validator) Validate(name string, qtype int) (string, error) {
+	name = dns.Normalize(name)
+	resp, err := v.exchange(name, qtype)
+
+	if err != nil {
+		return STATUS_INDETERMINATE, err
+	}
+
+	if len(resp.Answer) == 0 {
+		return v.validateNegative(name, qtype, resp)
+	}
+
+	return v.validateRecords(resp.Answer)
+}
+
+// Zones returns validation info for all checked zones
+func (v *Validator) Zones() []*Zone {
+	var result []*Zone
+
+	for _, zone := range v.zones {
+		result = append(result, zone)
+	}
+
+	slices.SortFunc(result, func(a, b *Zone) int {
+		return dns.CompareNames(a.Name, b.Name)
+	})
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// validateRecords validates all RRsets from given records
+func (v *Validator) validateRecords(records dns.Records) (string, error) {
+	status := STATUS_SECURE
+
+	for _, rrset := range groupRRsets(records) {
+		rrsetStatus, err := v.validateRRset(rrset, records)
+
+		switch rrsetStatus {
+		case STATUS_INSECURE:
+			status = STATUS_INSECURE
+		case STATUS_BOGUS, STATUS_INDETERMINATE:
+			return rrsetStatus, err
+		}
+	}
+
+	return status, nil
+}
+
+// validateNegative validates negative answer. Absence of name or records in
+// secure zone must be proved by signed NSEC/NSEC3 records.
+func (v *Validator) validateNegative(name string, qtype int, resp *dns.Message) (string, error) {
+	zone := v.validateZone(v.findZone(name))
+
+	if zone.Status != STATUS_SECURE {
+		return zone.Status, zone.Error
+	}
+
+	status, err := v.validateRecords(resp.Authority)
+
+	if status != STATUS_SECURE {
+		return status, err
+	}
+
+	proof := newDenial(zone.Name, resp.Authority)
+
+	if int(resp.Flags&0xF) == dns.STATUS_NXDOMAIN {
+		err = proof.proveNXDomain(name)
+	} else {
+		_, err = proof.proveNoData(name, qtype)
+	}
+
+	if err != nil {
+		return STATUS_BOGUS, fmt.Errorf("Can't prove absence of %s %s: %w", name, dns.TypeName(qtype), err)
+	}
+
+	return STATUS_SECURE, nil
+}
+
+// validateRRset validates RRset using signatures from given records
+func (v *Validator) validateRRset(rrset, records dns.Records) (string, error) {
+	owner := dns.Normalize(rrset[0].Name)
+	sigs := findSignatures(records, owner, rrset[0].Type)
+
+	if len(sigs) == 0 {
+		zone := v.validateZone(v.findZone(owner))
+
+		if zone.Status == STATUS_SECURE {
+			return STATUS_BOGUS, fmt.Errorf(
+				"%s %s isn't signed in secure zone %s",
+				owner, dns.TypeName(rrset[0].Type), zone.Name,
+			)
+		}
+
+		return zone.Status, zone.Error
+	}
+
+	var status string
+	var err error
+
+	// RRset can be signed by several zones (e.g. during algorithm rollover or
+	// if it's a delegation point), so every signer is checked
+	for _, signer := range getSigners(sigs) {
+		zone := v.validateZone(signer)
+
+		if zone.Status != STATUS_SECURE {
+			if status == "" {
+				status, err = zone.Status, zone.Error
+			}
+
+			continue
+		}
+
+		signerErr := verifyRRset(rrset, filterSignatures(sigs, signer), zone.keys)
+
+		if signerErr == nil {
+			return STATUS_SECURE, nil
+		}
+
+		status = STATUS_BOGUS
+		err = fmt.Errorf("%s %s: %w", owner, dns.TypeName(rrset[0].Type), signerErr)
+	}
+
+	return status, err
+}
+
+// validateZone validates keys of given zone using chain of trust from root zone
+func (v *Validator) validateZone(name string) *Zone {
+	if v.zones == nil {
+		v.zones = map[string]*Zone{}
+	}
+
+	if v.zones[name] != nil {
+		return v.zones[name]
+	}
+
+	zone := &Zone{Name: name}
+	v.zones[name] = zone
+
+	var dsSet []*ds
+
+	if name == "." {
+		dsSet, zone.Error = v.getAnchors()
+	} else {
+		dsSet, zone.Status, zone.Error = v.getDS(name)
+
+		if zone.Status != "" {
+			return zone
+		}
+	}
+
+	if zone.Error != nil {
+		zone.Status = STATUS_INDETERMINATE
+		return zone
+	}
+
+	zone.keys, zone.Error = v.getKeys(name, dsSet)
+
+	switch {
+	case zone.keys != nil:
+		zone.Status = STATUS_SECURE
+	case zone.Error != nil:
+		zone.Status = STATUS_BOGUS
+	}
+
+	return zone
+}
+
+// getDS returns validated DS records of zone from parent zone. If zone has no DS
+// records, status is returned instead.
+func (v *Validator) getDS(name string) ([]*ds, string, error) {
+	parent := v.validateZone(v.findZone(parentName(name)))
+
+	if parent.Status != STATUS_SECURE {
+		return nil, parent.Status, parent.Error
+	}
+
+	resp, err := v.exchange(name, dns.TYPE_DS)
+
+	if err != nil {
+		return nil, STATUS_INDETERMINATE, err
+	}
+
+	rrset := filterRecords(resp.Answer, name, dns.TYPE_DS)
+
+	if len(rrset) == 0 {
+		status, err := v.checkDenial(parent, name, resp.Authority)
+		return nil, status, err
+	}
+
+	err = verifyRRset(rrset, findSignatures(resp.Answer, name, dns.TYPE_DS), parent.keys)
+
+	if err != nil {
+		return nil, STATUS_BOGUS, fmt.Errorf("DS records of %s: %w", name, err)
+	}
+
+	var result []*ds
+
+	for _, r := range rrset {
+		record, err := parseDS(r)
+
+		if err != nil {
+			return nil, STATUS_BOGUS, fmt.Errorf("DS records of %s: %w", name, err)
+		}
+
+		result = append(result, record)
+	}
+
+	return result, "", nil
+}
+
+// checkDenial checks that absence of DS records is proved by signed NSEC/NSEC3
+// records of parent zone (RFC 4035, section 5.2 and RFC 5155, section 8.6)
+func (v *Validator) checkDenial(parent *Zone, name string, authority dns.Records) (string, error) {
+	for _, rrset := range groupRRsets(authority) {
+		rtype := rrset[0].Type
+
+		if rtype != dns.TYPE_NSEC && rtype != dns.TYPE_NSEC3 {
+			continue
+		}
+
+		owner := dns.Normalize(rrset[0].Name)
+		err := verifyRRset(rrset, findSignatures(authority, owner, rtype), parent.keys)
+
+		if err != nil {
+			return STATUS_BOGUS, fmt.Errorf("Denial of DS records of %s: %w", name, err)
+		}
+	}
+
+	_, err := newDenial(parent.Name, authority).proveNoData(name, dns.TYPE_DS)
+
+	if err != nil {
+		return STATUS_BOGUS, fmt.Errorf(
+			"Parent zone %s doesn't prove absence of DS records of %s: %w",
+			parent.Name, name, err,
+		)
+	}
+
+	return STATUS_INSECURE, nil
+}
+
+// getKeys returns keys of zone if DNSKEY RRset is signed by key which matches
+// one of given DS records
+func (v *Validator) getKeys(name string, dsSet []*ds) ([]*dnskey, error) {
+	resp, err := v.exchange(name, dns.TYPE_DNSKEY)
+
+	if err != nil {
+		return nil, err
+	}
+
+	rrset := filterRecords(resp.Answer, name, dns.TYPE_DNSKEY)
+
+	var keys, trusted []*dnskey
+
+	for _, r := range rrset {
+		key, err := parseDNSKEY(r)
+
+		if err != nil || key.flags&DNSKEY_FLAG_ZONE == 0 || key.protocol != DNSKEY_PROTOCOL {
+			continue
+		}
+
+		keys = append(keys, key)
+
+		if slices.ContainsFunc(dsSet, func(d *ds) bool { return d.matches(key) }) {
+			trusted = append(trusted, key)
+		}
+	}
+
+	if len(trusted) == 0 {
+		return nil, fmt.Errorf("There are no DNSKEY records of %s matching DS records", name)
+	}
+
+	err = verifyRRset(rrset, findSignatures(resp.Answer, name, dns.TYPE_DNSKEY), trusted)
+
+	if err != nil {
+		return nil, fmt.Errorf("DNSKEY records of %s: %w", name, err)
+	}
+
+	return keys, nil
+}
+
+// getAnchors returns parsed trust anchors
+func (v *Validator) getAnchors() ([]*ds, error) {
+	anchors := v.Anchors
+
+	if len(anchors) == 0 {
+		anchors = RootAnchors
+	}
+
+	var result []*ds
+
+	for _, anchor := range anchors {
+		var record ds
+		var digest string
+
+		_, err := fmt.Sscanf(
+			anchor, "%d %d %d %s",
+			&record.keyTag, &record.algorithm, &record.digestType, &digest,
+		)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse trust anchor %q: %w", anchor, err)
+		}
+
+		record.digest, err = hex.DecodeString(digest)
+
+		if err != nil {
+			return nil, fmt.Errorf("Can't parse trust anchor %q: %w", anchor, err)
+		}
+
+		result = append(result, &record)
+	}
+
+	return result, nil
+}
+
+// findZone returns name of zone which contains given name
+func (v *Validator) findZone(name string) string {
+	if name == "." {
+		return name
+	}
+
+	if v.cuts == nil {
+		v.cuts = map[string]string{}
+	}
+
+	if v.cuts[name] != "" {
+		return v.cuts[name]
+	}
+
+	zone := parentName(name)
+	resp, err := v.exchange(name, dns.TYPE_SOA)
+
+	if err == nil {
+		switch {
+		case len(filterRecords(resp.Answer, name, dns.TYPE_SOA)) != 0:
+			zone = name
+		case len(filterRecords(resp.Answer, name, dns.TYPE_CNAME)) != 0:
+			// Name with CNAME record can't be zone apex
+			zone = v.findZone(parentName(name))
+		default:
+			for _, r := range resp.Authority {
+				if r.Type == dns.TYPE_SOA {
+					zone = dns.Normalize(r.Name)
+				}
+			}
+		}
+	}
+
+	v.cuts[name] = zone
+
+	return zone
+}
+
+// exchange sends query with DNSSEC records request and disabled validation on
+// resolver side
+func (v *Validator) exchange(name string, qtype int) (*dns.Message, error) {
+	query := dns.NewQuery(name, qtype)
+	query.DNSSEC = true
+	query.Flags |= dns.FLAG_CD
+
+	resp, err := v.Exchanger.Exchange(query)
+
+	if err != nil {
+		return nil, err
+	}
+
+	status := int(resp.Flags & 0xF)
+
+	if status != dns.STATUS_NOERROR && status != dns.STATUS_NXDOMAIN {
+		return nil, fmt.Errorf("Resolver returned error status %d for %s", status, name)
+	}
+
+	return resp, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// verifyRRset checks that RRset is signed by one of given keys
+func verifyRRset(rrset dns.Records, sigs []*rrsig, keys []*dnskey) error {
+	if len(sigs) == 0 {
+		return fmt.Errorf("There are no signatures")
+	}
+
+	err := errBadSignature
+	now := time.Now()
+
+	for _, sig := range sigs {
+		if !sig.isValidAt(now) {
+			err = fmt.Errorf("Signature with key tag %d is expired or not yet valid", sig.keyTag)
+			continue
+		}
+
+		for _, key := range keys {
+			if sig.verify(key, rrset) == nil {
+				return nil
+			}
+		}
+	}
+
+	return err
+}
+
+// findSignatures returns signatures for RRset with given owner and type. Only
+// signatures made by zone containing RRset (owner itself or one of its parents)
+// are returned (RFC 4035, section 5.3.1).
+func findSignatures(records dns.Records, owner string, rtype int) []*rrsig {
+	var result []*rrsig
+
+	for _, r := range filterRecords(records, owner, dns.TYPE_RRSIG) {
+		sig, err := parseRRSIG(r)
+
+		if err == nil && sig.typeCovered == rtype && isInZone(owner, sig.signer) {
+			result = append(result, sig)
+		}
+	}
+
+	return result
+}
+
+// filterSignatures returns signatures made by given signer
+func filterSignatures(sigs []*rrsig, signer string) []*rrsig {
+	var result []*rrsig
+
+	for _, sig := range sigs {
+		if sig.signer == signer {
+			result = append(result, sig)
+		}
+	}
+
+	return result
+}
+
+// getSigners returns unique signers of given signatures
+func getSigners(sigs []*rrsig) []string {
+	var result []string
+
+	for _, sig := range sigs {
+		if !slices.Contains(result, sig.signer) {
+			result = append(result, sig.signer)
+		}
+	}
+
+	return result
+}
+
+// groupRRsets groups records (except signatures) to RRsets
+func groupRRsets(records dns.Records) []dns.Records {
+	var result []dns.Records
+
+	index := map[string]int{}
+
+	for _, r := range records {
+		if r.Type == dns.TYPE_RRSIG {
+			continue
+		}
+
+		key := dns.Normalize(r.Name) + "/" + dns.TypeName(r.Type)
+		i, ok := index[key]
+
+		if !ok {
+			i = len(result)
+			index[key] = i
+			result = append(result, nil)
+		}
+
+		result[i] = append(result[i], r)
+	}
+
+	return result
+}
+
+// filterRecords returns records with given owner and type
+func filterRecords(records dns.Records, owner string, rtype int) dns.Records {
+	var result dns.Records
+
+	for _, r := range records {
+		if r.Type == rtype && dns.Normalize(r.Name) == owner {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+// parentName returns name of parent domain
+func parentName(name string) string {
+	_, parent, ok := strings.Cut(name, ".")
+
+	if !ok || parent == "" {
+		return "."
+	}
+
+	return parent
+}
@@ -0,0 +1,199 @@
+package dnssec
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// testRR is record in wire format
+type testRR struct {
+	name  string
+	rtype int
+	rdata []byte
+}
+
+// testZone is signed zone with Ed25519 key
+type testZone struct {
+	name  string
+	key   ed25519.PrivateKey
+	rdata []byte
+}
+
+// testExchanger is exchanger with predefined answers
+type testExchanger map[string][]testRR
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+func TestValidatorSigners(t *testing.T) {
+	root := newTestZone(t, ".")
+	example := newTestZone(t, "example")
+	evil := newTestZone(t, "evil")
+
+	ex := testExchanger{}
+	ex.add(t, root, testRR{".", dns.TYPE_DNSKEY, root.rdata})
+
+	for _, zone := range []*testZone{example, evil} {
+		ex.add(t, root, testRR{zone.name, dns.TYPE_DS, zone.dsRData()})
+		ex.add(t, zone, testRR{zone.name, dns.TYPE_DNSKEY, zone.rdata})
+		ex.add(t, nil, testRR{zone.name, dns.TYPE_SOA, soaRData(zone.name)})
+	}
+
+	a := testRR{"www.example", dns.TYPE_A, []byte{192, 0, 2, 1}}
+
+	ex.add(t, example, a)
+
+	// Valid signature made by key of another secure zone
+	ex.add(t, evil, testRR{"foreign.example", dns.TYPE_A, a.rdata})
+
+	// Bogus signature of root zone must not hide valid signature of example zone
+	multi := testRR{"multi.example", dns.TYPE_A, a.rdata}
+	badSig := root.sign(t, testRR{"multi.example", dns.TYPE_A, []byte{192, 0, 2, 2}})
+	ex.add(t, nil, multi, badSig, example.sign(t, multi))
+
+	v := &Validator{Exchanger: ex, Anchors: []string{root.ds()}}
+
+	for _, c := range []struct {
+		name   string
+		status string
+	}{
+		{"www.example", STATUS_SECURE},
+		{"foreign.example", STATUS_BOGUS},
+		{"multi.example", STATUS_SECURE},
+	} {
+		status, err := v.Validate(c.name, dns.TYPE_A)
+
+		if status != c.status {
+			t.Fatalf("Invalid status of %s: expected %s, got %s (%v)", c.name, c.status, status, err)
+		}
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Exchange returns predefined answer for query
+func (e testExchanger) Exchange(query *dns.Message) (*dns.Message, error) {
+	q := query.Question[0]
+	answer := e[testKey(q.Name, q.Type)]
+
+	msg := make([]byte, 12)
+	binary.BigEndian.PutUint16(msg, query.ID)
+	binary.BigEndian.PutUint16(msg[2:], dns.FLAG_QR)
+	binary.BigEndian.PutUint16(msg[4:], 1)
+	binary.BigEndian.PutUint16(msg[6:], uint16(len(answer)))
+
+	msg = append(msg, wireName(q.Name)...)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(q.Type))
+	msg = binary.BigEndian.AppendUint16(msg, dns.CLASS_INET)
+
+	for _, r := range answer {
+		msg = appendTestRR(msg, string(wireName(r.name)), r.rtype, 3600, r.rdata)
+	}
+
+	return dns.Unpack(msg)
+}
+
+// add adds record signed by given zone (if set) with extra records to answers
+func (e testExchanger) add(t *testing.T, signer *testZone, r testRR, extra ...testRR) {
+	key := testKey(r.name, r.rtype)
+	e[key] = append(e[key], r)
+
+	if signer != nil {
+		e[key] = append(e[key], signer.sign(t, r))
+	}
+
+	e[key] = append(e[key], extra...)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// newTestZone creates zone with new Ed25519 key
+func newTestZone(t *testing.T, name string) *testZone {
+	t.Helper()
+
+	pub, priv, err := ed25519.GenerateKey(nil)
+
+	if err != nil {
+		t.Fatalf("Can't generate key: %v", err)
+	}
+
+	rdata := []byte{0x01, 0x01, DNSKEY_PROTOCOL, ALG_ED25519}
+
+	return &testZone{name, priv, append(rdata, pub...)}
+}
+
+// ds returns DS record of zone key in presentation format
+func (z *testZone) ds() string {
+	digest := z.dsRData()[4:]
+
+	return fmt.Sprintf(
+		"%d %d %d %s", keyTag(z.rdata), ALG_ED25519,
+		DIGEST_SHA256, hex.EncodeToString(digest),
+	)
+}
+
+// dsRData returns DS record of zone key in wire format
+func (z *testZone) dsRData() []byte {
+	digest := sha256.Sum256(append(wireName(z.name), z.rdata...))
+
+	rdata := binary.BigEndian.AppendUint16(nil, keyTag(z.rdata))
+	rdata = append(rdata, ALG_ED25519, DIGEST_SHA256)
+
+	return append(rdata, digest[:]...)
+}
+
+// sign returns RRSIG record for given record
+func (z *testZone) sign(t *testing.T, r testRR) testRR {
+	t.Helper()
+
+	now := uint32(time.Now().Unix())
+	record := newTestRecord(t, r.name, r.rtype, r.rdata)
+	labels := uint8(countLabels(wireName(r.name)))
+
+	header := binary.BigEndian.AppendUint16(nil, uint16(r.rtype))
+	header = append(header, ALG_ED25519, labels)
+	header = binary.BigEndian.AppendUint32(header, 3600)
+	header = binary.BigEndian.AppendUint32(header, now+3600)
+	header = binary.BigEndian.AppendUint32(header, now-3600)
+	header = binary.BigEndian.AppendUint16(header, keyTag(z.rdata))
+	header = append(header, wireName(z.name)...)
+
+	data, err := signedData(&rrsig{labels: labels, origTTL: 3600, header: header}, dns.Records{record})
+
+	if err != nil {
+		t.Fatalf("Can't build signed data: %v", err)
+	}
+
+	return testRR{r.name, dns.TYPE_RRSIG, append(header, ed25519.Sign(z.key, data)...)}
+}
+
+// soaRData returns SOA record of zone in wire format
+func soaRData(zone string) []byte {
+	rdata := append(wireName("ns."+zone), wireName("hostmaster."+zone)...)
+
+	for range 5 {
+		rdata = binary.BigEndian.AppendUint32(rdata, 3600)
+	}
+
+	return rdata
+}
+
+// testKey returns key of answer for given name and type
+func testKey(name string, rtype int) string {
+	return fmt.Sprintf("%s/%d", dns.Normalize(name), rtype)
+}
@@ -0,0 +1,243 @@
+package permute
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_LIMIT is default maximum number of candidates
+const DEFAULT_LIMIT = 5000
+
+// MAX_NUM_STEP is maximum increment/decrement of numbers in names
+const MAX_NUM_STEP = 3
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config contains permutation configuration
+type Config struct {
+	Limit int      // Maximum number of candidates (default: 5000)
+	Words []string // Words for adding to names (default: built-in list)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// envTokens contains tokens used for environment names
+var envTokens = []string{
+	"dev", "develop", "development", "test", "testing", "qa", "uat",
+	"stage", "staging", "stg", "preprod", "prod", "production", "demo",
+	"sandbox", "beta", "canary", "int", "internal",
+}
+
+// defaultWords contains words used for adding to names
+var defaultWords = []string{
+	"dev", "stage", "staging", "test", "qa", "uat", "prod", "beta", "demo",
+	"api", "admin", "internal", "new", "old", "v1", "v2", "backup", "lb",
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// strategies contains candidate generators ordered by their usefulness. Candidates
+// from the first strategies are added first, so they survive the limit.
+var strategies = []func(label string, words []string) []string{
+	swapEnv, changeNumbers, removeParts, addParts, addLabels,
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Generate generates candidates using given known names of the domain. Known names
+// are never returned as candidates.
+func Generate(names []string, domain string, config Config) []string {
+	limit, words := config.Limit, config.Words
+
+	if limit <= 0 {
+		limit = DEFAULT_LIMIT
+	}
+
+	if len(words) == 0 {
+		words = defaultWords
+	}
+
+	var result []string
+
+	domain = strings.ToLower(strings.Trim(domain, "."))
+	seen := map[string]bool{}
+
+	for _, name := range names {
+		seen[strings.ToLower(name)] = true
+	}
+
+	for _, strategy := range strategies {
+		for _, name := range names {
+			label, parent, ok := splitName(strings.ToLower(name), domain)
+
+			if !ok {
+				continue
+			}
+
+			for _, candidate := range strategy(label, words) {
+				if !isValidLabel(candidate) {
+					continue
+				}
+
+				candidate += "." + parent
+
+				if seen[candidate] {
+					continue
+				}
+
+				seen[candidate] = true
+				result = append(result, candidate)
+
+				if len(result) >= limit {
+					return result
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// splitName splits name to the first label and parent name. Only names which
+// are subdomains of given domain can be split.
+func splitName(name, domain string) (string, string, bool) {
+	if !strings.HasSuffix(name, "."+domain) {
+		return "", "", false
+	}
+
+	label, parent, _ := strings.Cut(name, ".")
+
+	return label, parent, label != "" && label != "*"
+}
+
+// swapEnv replaces environment tokens with other environment tokens
+// (api-dev → api-stage)
+func swapEnv(label string, _ []string) []string {
+	var result []string
+
+	parts := strings.Split(label, "-")
+
+	for i, part := range parts {
+		if !slices.Contains(envTokens, part) {
+			continue
+		}
+
+		for _, token := range envTokens {
+			if token != part {
+				result = append(result, joinParts(parts, i, token))
+			}
+		}
+	}
+
+	return result
+}
+
+// changeNumbers increments and decrements numbers in label keeping zero padding
+// (web01 → web02)
+func changeNumbers(label string, _ []string) []string {
+	var result []string
+
+	parts := strings.Split(label, "-")
+
+	for i, part := range parts {
+		prefix := strings.TrimRight(part, "0123456789")
+		digits := part[len(prefix):]
+
+		if digits == "" || len(digits) > 6 {
+			continue
+		}
+
+		num, _ := strconv.Atoi(digits)
+
+		for step := -MAX_NUM_STEP; step <= MAX_NUM_STEP; step++ {
+			if step == 0 || num+step < 0 {
+				continue
+			}
+
+			newPart := prefix + fmt.Sprintf("%0*d", len(digits), num+step)
+			result = append(result, joinParts(parts, i, newPart))
+		}
+	}
+
+	return result
+}
+
+// removeParts removes hyphenated parts from label (api-dev → api)
+func removeParts(label string, _ []string) []string {
+	var result []string
+
+	parts := strings.Split(label, "-")
+
+	if len(parts) < 2 {
+		return nil
+	}
+
+	for i := range parts {
+		result = append(result, strings.Join(slices.Delete(slices.Clone(parts), i, i+1), "-"))
+	}
+
+	return result
+}
+
+// addParts adds words as hyphenated parts to label (api → api-dev, dev-api)
+func addParts(label string, words []string) []string {
+	var result []string
+
+	parts := strings.Split(label, "-")
+
+	for _, word := range words {
+		if slices.Contains(parts, word) {
+			continue
+		}
+
+		result = append(result, label+"-"+word, word+"-"+label)
+	}
+
+	return result
+}
+
+// addLabels prepends words as new labels (api → dev.api)
+func addLabels(label string, words []string) []string {
+	var result []string
+
+	for _, word := range words {
+		if word != label {
+			result = append(result, word+"."+label)
+		}
+	}
+
+	return result
+}
+
+// joinParts joins parts replacing part with given index
+func joinParts(parts []string, index int, part string) string {
+	parts = slices.Clone(parts)
+	parts[index] = part
+
+	return strings.Join(parts, "-")
+}
+
+// isValidLabel returns true if all labels in given name are valid
+func isValidLabel(name string) bool {
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 ||
+			strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+	}
+
+	return true
+}
@@ -8,7 +8,6 @@ package probe
 // ////////////////////////////////////////////////////////////////////////////////// //
 
 import (
-	"fmt"
 	"net"
 	"strconv"
 	"time"
@@ -63,7 +62,7 @@ var probeCache, _ = cache.New(cache.Config{DefaultExpiration: time.Hour})
 
 // ////////////////////////////////////////////////////////////////////////////////// //
 
-// Probe probes given IPs for accessible ports
+// Probe probes given IPv4 and IPv6 addresses for accessible ports
 func Probe(ips []string) []string {
 	if len(ips) == 0 {
 		return nil
@@ -73,7 +72,7 @@ func Probe(ips []string) []string {
 
 	for _, ip := range ips {
 		for _, port := range ports {
-			addr := fmt.Sprintf("%s:%d", ip, port)
+			addr := net.JoinHostPort(ip, strconv.Itoa(port))
 
 			if !probeCache.Has(addr) {
 				_, err := net.DialTimeout("tcp", addr, time.Second/10)
@@ -0,0 +1,123 @@
+package reverse
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/subdy/dns"
+	"github.com/essentialkaos/subdy/workers"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Config contains sweep configuration
+type Config struct {
+	Workers  int                   // Number of concurrent workers (default: 16)
+	Rate     int                   // Maximum number of queries per second (0 = no limit)
+	Progress func(done, total int) // Progress handler (optional)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Lookup returns PTR records for given IP address
+func Lookup(resolver dns.Resolver, ip string) dns.Records {
+	name, err := dns.ReverseName(ip)
+
+	if err != nil {
+		return nil
+	}
+
+	answer, err := resolver.Resolve(name, dns.TYPE_PTR)
+
+	if err != nil || answer.Status != dns.STATUS_NOERROR {
+		return nil
+	}
+
+	var result dns.Records
+
+	for _, r := range answer.Records {
+		// Skip CNAME chain (RFC 2317 classless delegation)
+		if r.Type == dns.TYPE_PTR {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+// Networks returns networks with given prefix length which contain given IPv4
+// addresses. IPv6 addresses are ignored, because their networks are too large
+// for sweeping.
+func Networks(ips []string, bits int) []netip.Prefix {
+	var result []netip.Prefix
+
+	for _, ip := range ips {
+		addr, err := netip.ParseAddr(ip)
+
+		if err != nil || !addr.Unmap().Is4() {
+			continue
+		}
+
+		network, err := addr.Unmap().Prefix(bits)
+
+		if err == nil && !slices.Contains(result, network) {
+			result = append(result, network)
+		}
+	}
+
+	return result
+}
+
+// Sweep performs PTR lookups for all addresses in given networks and returns
+// names which belong to given domain
+func Sweep(resolver dns.Resolver, networks []netip.Prefix, domain string, config Config) []string {
+	var addrs []string
+
+	for _, network := range networks {
+		for addr := network.Addr(); network.Contains(addr); addr = addr.Next() {
+			addrs = append(addrs, addr.String())
+		}
+	}
+
+	resolver = dns.Limit(resolver, config.Rate)
+	names := make([]dns.Records, len(addrs))
+
+	workers.Run(
+		len(addrs), config.Workers,
+		func(index int) {
+			names[index] = Lookup(resolver, addrs[index])
+		},
+		config.Progress,
+	)
+
+	return Names(slices.Concat(names...), domain)
+}
+
+// Names returns names from PTR records which belong to given domain
+func Names(records dns.Records, domain string) []string {
+	var result []string
+
+	domain = strings.ToLower(strings.Trim(domain, "."))
+
+	for _, r := range records {
+		if r.Type != dns.TYPE_PTR {
+			continue
+		}
+
+		name := strings.ToLower(strings.TrimRight(r.Data, "."))
+
+		if strings.HasSuffix(name, "."+domain) && !slices.Contains(result, name) {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
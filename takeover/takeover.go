@@ -0,0 +1,244 @@
+package takeover
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/essentialkaos/ek/v13/req"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_TIMEOUT is default timeout for HTTP requests
+const DEFAULT_TIMEOUT = 10 * time.Second
+
+// MAX_BODY_SIZE is maximum size of response body checked for fingerprint (1 MiB)
+const MAX_BODY_SIZE = 1024 * 1024
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Fingerprint contains info about service which allows subdomain takeover
+type Fingerprint struct {
+	Service  string   `json:"service"`     // Service name
+	CNAMEs   []string `json:"cname"`       // Parts of CNAME targets of service
+	Body     string   `json:"fingerprint"` // Part of HTTP response body for unclaimed resource
+	Status   int      `json:"status"`      // HTTP status code for unclaimed resource (optional)
+	NXDomain bool     `json:"nxdomain"`    // Unclaimed resource has no DNS records
+}
+
+// Checker checks subdomains for possible takeover
+type Checker struct {
+	Resolver     dns.Resolver   // Resolver for subdomains without answer
+	Fingerprints []*Fingerprint // Services fingerprints (default: built-in database)
+	Timeout      time.Duration  // HTTP requests timeout (default: 10s)
+}
+
+// Candidate contains info about subdomain which can be taken over
+type Candidate struct {
+	Name     string // Subdomain
+	Target   string // Final CNAME target
+	Service  string // Service name (empty for unknown services)
+	Evidence string // Evidence of vulnerability
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+//go:embed fingerprints.json
+var defaultFingerprints []byte
+
+// parseDefaultFingerprints decodes built-in fingerprints database only once
+var parseDefaultFingerprints = sync.OnceValue(func() []*Fingerprint {
+	var result []*Fingerprint
+
+	json.Unmarshal(defaultFingerprints, &result)
+
+	return result
+})
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DefaultFingerprints returns built-in fingerprints database
+func DefaultFingerprints() []*Fingerprint {
+	return parseDefaultFingerprints()
+}
+
+// ReadFingerprints reads fingerprints database from file
+func ReadFingerprints(file string) ([]*Fingerprint, error) {
+	data, err := os.ReadFile(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't read fingerprints database: %w", err)
+	}
+
+	var result []*Fingerprint
+
+	err = json.Unmarshal(data, &result)
+
+	if err != nil {
+		return nil, fmt.Errorf("Can't decode fingerprints database: %w", err)
+	}
+
+	for index, fp := range result {
+		if fp == nil || fp.Service == "" || len(fp.CNAMEs) == 0 || (fp.Body == "" && !fp.NXDomain) {
+			return nil, fmt.Errorf("Fingerprint #%d in database is invalid", index+1)
+		}
+	}
+
+	return result, nil
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Check checks if subdomain with given answer (A/AAAA records with CNAME chain)
+// can be taken over. If answer is nil, subdomain will be resolved.
+func (c *Checker) Check(name string, answer *dns.Answer) *Candidate {
+	if answer == nil {
+		var err error
+
+		answer, err = c.Resolver.Resolve(name, dns.TYPE_A)
+
+		if err != nil {
+			return nil
+		}
+	}
+
+	target := getTarget(answer)
+
+	if target == "" {
+		return nil
+	}
+
+	fp := c.findFingerprint(target)
+
+	switch {
+	case answer.Status == dns.STATUS_NXDOMAIN:
+		// Missing target of known service can be claimed only if service
+		// fingerprint is NXDOMAIN
+		if fp != nil && !fp.NXDomain {
+			return nil
+		}
+
+		candidate := &Candidate{
+			Name:     name,
+			Target:   target,
+			Evidence: fmt.Sprintf("CNAME target %s doesn't exist (NXDOMAIN)", target),
+		}
+
+		if fp != nil {
+			candidate.Service = fp.Service
+		}
+
+		return candidate
+
+	case fp == nil || fp.Body == "":
+		return nil
+	}
+
+	evidence := checkHTTP(name, fp, c.getTimeout())
+
+	if evidence == "" {
+		return nil
+	}
+
+	return &Candidate{
+		Name:     name,
+		Target:   target,
+		Service:  fp.Service,
+		Evidence: evidence,
+	}
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// String returns string representation of candidate
+func (c *Candidate) String() string {
+	if c.Service == "" {
+		return fmt.Sprintf("%s → %s: %s", c.Name, c.Target, c.Evidence)
+	}
+
+	return fmt.Sprintf("%s → %s (%s): %s", c.Name, c.Target, c.Service, c.Evidence)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// findFingerprint returns fingerprint of service with given CNAME target
+func (c *Checker) findFingerprint(target string) *Fingerprint {
+	fingerprints := c.Fingerprints
+
+	if len(fingerprints) == 0 {
+		fingerprints = DefaultFingerprints()
+	}
+
+	for _, fp := range fingerprints {
+		for _, cname := range fp.CNAMEs {
+			if strings.Contains(target, strings.ToLower(cname)) {
+				return fp
+			}
+		}
+	}
+
+	return nil
+}
+
+// getTimeout returns HTTP requests timeout
+func (c *Checker) getTimeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DEFAULT_TIMEOUT
+	}
+
+	return c.Timeout
+}
+
+// getTarget returns the final target of CNAME chain from answer
+func getTarget(answer *dns.Answer) string {
+	var target string
+
+	for _, r := range answer.Records {
+		if r.Type == dns.TYPE_CNAME {
+			target = strings.ToLower(strings.TrimRight(r.Data, "."))
+		}
+	}
+
+	return target
+}
+
+// checkHTTP sends HTTP requests to subdomain and checks if response matches
+// fingerprint. It returns evidence if response matches.
+func checkHTTP(name string, fp *Fingerprint, timeout time.Duration) string {
+	// Some services (e.g. S3 website endpoints) support only plain HTTP
+	for _, url := range []string{"https://" + name, "http://" + name} {
+		resp, err := req.Request{URL: url, Timeout: timeout}.Get()
+
+		if err != nil {
+			continue
+		}
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, MAX_BODY_SIZE))
+		resp.Body.Close()
+
+		if err != nil || (fp.Status != 0 && resp.StatusCode != fp.Status) {
+			continue
+		}
+
+		if strings.Contains(string(body), fp.Body) {
+			return fmt.Sprintf("Response from %s contains %q", url, fp.Body)
+		}
+	}
+
+	return ""
+}
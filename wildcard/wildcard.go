@@ -0,0 +1,227 @@
+package wildcard
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_PROBES is default number of random labels resolved for every zone
+const DEFAULT_PROBES = 3
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Wildcard contains fingerprint of wildcard record
+type Wildcard struct {
+	Zone    string   // Zone with wildcard record
+	IPs     []string // IP addresses returned for random names
+	Targets []string // CNAME targets returned for random names
+}
+
+// Detector detects wildcard records
+type Detector struct {
+	Resolver dns.Resolver
+	Probes   int // Number of random labels resolved for every zone (default: 3)
+
+	zones map[string]*zoneEntry
+	mu    sync.Mutex
+}
+
+// zoneEntry is wildcard detection result for zone. Concurrent checks of the same
+// zone wait for the first one.
+type zoneEntry struct {
+	wg       sync.WaitGroup
+	wildcard *Wildcard
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Detect checks if given zone has wildcard record and returns its fingerprint
+func (d *Detector) Detect(zone string) *Wildcard {
+	zone = dns.Normalize(zone)
+
+	d.mu.Lock()
+
+	if d.zones == nil {
+		d.zones = map[string]*zoneEntry{}
+	}
+
+	entry := d.zones[zone]
+
+	if entry != nil {
+		d.mu.Unlock()
+		entry.wg.Wait()
+		return entry.wildcard
+	}
+
+	entry = &zoneEntry{}
+	entry.wg.Add(1)
+	d.zones[zone] = entry
+
+	d.mu.Unlock()
+
+	entry.wildcard = d.detect(zone)
+	entry.wg.Done()
+
+	return entry.wildcard
+}
+
+// Match checks if given answer for given name matches wildcard record of parent
+// zone and returns this wildcard
+func (d *Detector) Match(name string, answer *dns.Answer) *Wildcard {
+	_, zone, ok := strings.Cut(dns.Normalize(name), ".")
+
+	if !ok || !strings.Contains(zone, ".") || answer.IsEmpty() {
+		return nil
+	}
+
+	wc := d.Detect(zone)
+
+	if wc == nil || !wc.Matches(answer) {
+		return nil
+	}
+
+	return wc
+}
+
+// Wildcards returns all detected wildcards
+func (d *Detector) Wildcards() []*Wildcard {
+	d.mu.Lock()
+	entries := slices.Collect(maps.Values(d.zones))
+	d.mu.Unlock()
+
+	var result []*Wildcard
+
+	for _, entry := range entries {
+		entry.wg.Wait()
+
+		if entry.wildcard != nil {
+			result = append(result, entry.wildcard)
+		}
+	}
+
+	slices.SortFunc(result, func(a, b *Wildcard) int {
+		return strings.Compare(a.Zone, b.Zone)
+	})
+
+	return result
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Matches returns true if given answer matches wildcard fingerprint
+func (w *Wildcard) Matches(answer *dns.Answer) bool {
+	if w == nil || answer.IsEmpty() {
+		return false
+	}
+
+	target := cnameTarget(answer)
+
+	if target != "" && slices.Contains(w.Targets, target) {
+		return true
+	}
+
+	ips := append(answer.IP(), answer.IPv6()...)
+
+	if len(ips) == 0 {
+		return false
+	}
+
+	for _, ip := range ips {
+		if !slices.Contains(w.IPs, ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns string representation of wildcard
+func (w *Wildcard) String() string {
+	if w == nil {
+		return ""
+	}
+
+	return "*." + w.Zone + " → " + strings.Join(slices.Concat(w.Targets, w.IPs), " / ")
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// add adds data from answer to fingerprint
+func (w *Wildcard) add(answer *dns.Answer) {
+	target := cnameTarget(answer)
+
+	if target != "" && !slices.Contains(w.Targets, target) {
+		w.Targets = append(w.Targets, target)
+	}
+
+	for _, ip := range append(answer.IP(), answer.IPv6()...) {
+		if !slices.Contains(w.IPs, ip) {
+			w.IPs = append(w.IPs, ip)
+		}
+	}
+
+	slices.Sort(w.IPs)
+}
+
+// detect resolves random names in given zone and returns wildcard fingerprint
+func (d *Detector) detect(zone string) *Wildcard {
+	var wc *Wildcard
+
+	probes := d.Probes
+
+	if probes <= 0 {
+		probes = DEFAULT_PROBES
+	}
+
+	for range probes {
+		answer := d.resolve(dns.RandomLabel() + "." + zone)
+
+		if answer.IsEmpty() || answer.Status != dns.STATUS_NOERROR {
+			continue
+		}
+
+		if wc == nil {
+			wc = &Wildcard{Zone: zone}
+		}
+
+		wc.add(answer)
+	}
+
+	return wc
+}
+
+// resolve resolves both IPv4 and IPv6 addresses of given name
+func (d *Detector) resolve(name string) *dns.Answer {
+	ipv4, _ := d.Resolver.Resolve(name, dns.TYPE_A)
+	ipv6, _ := d.Resolver.Resolve(name, dns.TYPE_AAAA)
+
+	return ipv4.Merge(ipv6)
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// cnameTarget returns the last target in CNAME chain
+func cnameTarget(answer *dns.Answer) string {
+	var target string
+
+	for _, r := range answer.Records {
+		if r.Type == dns.TYPE_CNAME {
+			target = dns.Normalize(r.Data)
+		}
+	}
+
+	return target
+}
@@ -0,0 +1,59 @@
+package workers
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"sync"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_WORKERS is default number of workers
+const DEFAULT_WORKERS = 16
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Run calls worker for every index from 0 to total-1 using bounded pool of
+// goroutines. Progress handler (optional) is called after every processed index.
+func Run(total, workers int, worker func(index int), progress func(done, total int)) {
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+
+	if workers <= 0 {
+		workers = DEFAULT_WORKERS
+	}
+
+	indexChan := make(chan int)
+	done := 0
+
+	for range min(workers, total) {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for index := range indexChan {
+				worker(index)
+
+				if progress != nil {
+					mu.Lock()
+					done++
+					progress(done, total)
+					mu.Unlock()
+				}
+			}
+		}()
+	}
+
+	for index := range total {
+		indexChan <- index
+	}
+
+	close(indexChan)
+	wg.Wait()
+}
@@ -0,0 +1,343 @@
+package zonewalk
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+//                                                                                    //
+//                         Copyright (c) 2025 ESSENTIAL KAOS                          //
+//      Apache License, Version 2.0 <https://www.apache.org/licenses/LICENSE-2.0>     //
+//                                                                                    //
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/essentialkaos/subdy/dns"
+)
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// DEFAULT_MAX_QUERIES is default maximum number of queries for one zone
+const DEFAULT_MAX_QUERIES = 5000
+
+// MAX_HASH_ATTEMPTS is maximum number of random names hashed for finding name
+// which isn't covered by already collected NSEC3 records
+const MAX_HASH_ATTEMPTS = 10000
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Walker enumerates names of DNSSEC-signed zones using NSEC and NSEC3 records
+type Walker struct {
+	Exchanger  dns.Exchanger // Wire-format resolver (authoritative server is preferred)
+	MaxQueries int           // Maximum number of queries (default: 5000)
+}
+
+// Result contains zone walking result
+type Result struct {
+	Zone     string   // Zone name
+	Names    []string // Names from NSEC chain or cracked NSEC3 hashes
+	Hashes   []string // Collected NSEC3 hashes (only for NSEC3 zones)
+	Complete bool     // Whole chain was walked
+
+	salt       string
+	iterations int
+	cracked    map[string]bool
+}
+
+// hashChain is chain of NSEC3 hashes
+type hashChain struct {
+	next   map[string]string // Owner hash → next hash
+	owners []string          // Sorted owner hashes
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// ErrNotSigned is returned if zone doesn't use authenticated denial of existence
+var ErrNotSigned = errors.New("Zone isn't signed or doesn't return NSEC/NSEC3 records")
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Walk walks NSEC chain or collects NSEC3 hashes of given zone. Walker returns
+// partial result with error if walking was interrupted.
+func (w *Walker) Walk(zone string) (*Result, error) {
+	zone = dns.Normalize(zone)
+	resp, err := w.exchange(dns.RandomLabel()+"."+zone, dns.TYPE_A)
+
+	if err != nil {
+		return nil, err
+	}
+
+	records := append(resp.Answer, resp.Authority...)
+
+	switch {
+	case slices.ContainsFunc(records, isType(dns.TYPE_NSEC)):
+		return w.walkNSEC(zone)
+	case slices.ContainsFunc(records, isType(dns.TYPE_NSEC3)):
+		return w.walkNSEC3(zone, records)
+	}
+
+	return nil, ErrNotSigned
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// Crack hashes given words as labels of zone and adds names with matching NSEC3
+// hashes to result. It returns number of cracked hashes.
+func (r *Result) Crack(words []string) int {
+	if len(r.Hashes) == 0 {
+		return 0
+	}
+
+	if r.cracked == nil {
+		r.cracked = map[string]bool{}
+	}
+
+	cracked := 0
+
+	for _, word := range words {
+		name := word + "." + r.Zone
+		hash, err := dns.HashName(name, r.salt, r.iterations)
+
+		if err != nil || r.cracked[hash] {
+			continue
+		}
+
+		_, found := slices.BinarySearch(r.Hashes, hash)
+
+		if found {
+			r.cracked[hash] = true
+			r.Names = append(r.Names, name)
+			cracked++
+		}
+	}
+
+	return cracked
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// walkNSEC follows NSEC chain from zone apex
+func (w *Walker) walkNSEC(zone string) (*Result, error) {
+	result := &Result{Zone: zone}
+	seen := map[string]bool{zone: true}
+
+	for name := zone; len(seen) < w.getMaxQueries(); {
+		next, err := w.nextName(zone, name)
+
+		if err != nil {
+			return result, err
+		}
+
+		if next == zone {
+			result.Complete = true
+			return result, nil
+		}
+
+		if seen[next] || !isSubdomain(next, zone) {
+			return result, fmt.Errorf("NSEC chain of %s is broken at %s", zone, name)
+		}
+
+		seen[next] = true
+		name = next
+
+		// Wildcard records don't define real names
+		if !strings.HasPrefix(next, "*.") {
+			result.Names = append(result.Names, next)
+		}
+	}
+
+	return result, fmt.Errorf("Reached maximum number of queries for %s", zone)
+}
+
+// nextName returns next name in NSEC chain after given name
+func (w *Walker) nextName(zone, name string) (string, error) {
+	// \000.name is the first possible name after given name in canonical order,
+	// so the server must return NSEC record which covers it
+	succ := "\\000." + name
+	resp, err := w.exchange(succ, dns.TYPE_A)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range append(resp.Answer, resp.Authority...) {
+		nsec, owner := r.NSEC(), dns.Normalize(r.Name)
+
+		if nsec == nil {
+			continue
+		}
+
+		next := dns.Normalize(nsec.Next)
+
+		// Server can return NSEC of delegation point with referral
+		if owner == name || dns.CoversName(owner, next, succ, zone) {
+			return next, nil
+		}
+	}
+
+	// Fallback for delegation points and servers without NSEC in negative responses
+	resp, err = w.exchange(name, dns.TYPE_NSEC)
+
+	if err != nil {
+		return "", err
+	}
+
+	for _, r := range resp.Answer {
+		if r.Type == dns.TYPE_NSEC && dns.Normalize(r.Name) == name {
+			return dns.Normalize(r.NSEC().Next), nil
+		}
+	}
+
+	return "", fmt.Errorf("Can't find NSEC record for %s", name)
+}
+
+// walkNSEC3 collects NSEC3 hashes of zone
+func (w *Walker) walkNSEC3(zone string, records dns.Records) (*Result, error) {
+	chain := &hashChain{next: map[string]string{}}
+	result := &Result{Zone: zone, iterations: -1}
+
+	chain.addRecords(result, zone, records)
+
+	if result.iterations < 0 {
+		return nil, ErrNotSigned
+	}
+
+	defer func() {
+		result.Hashes = chain.owners
+		result.Complete = chain.isComplete()
+	}()
+
+	for queries := 1; !chain.isComplete(); queries++ {
+		if queries >= w.getMaxQueries() {
+			return result, fmt.Errorf("Reached maximum number of queries for %s", zone)
+		}
+
+		name := chain.findUncovered(result, zone)
+
+		if name == "" {
+			break
+		}
+
+		resp, err := w.exchange(name, dns.TYPE_A)
+
+		if err != nil {
+			return result, err
+		}
+
+		chain.addRecords(result, zone, append(resp.Answer, resp.Authority...))
+	}
+
+	return result, nil
+}
+
+// exchange sends query with DNSSEC records request
+func (w *Walker) exchange(name string, qtype int) (*dns.Message, error) {
+	query := dns.NewQuery(name, qtype)
+	query.DNSSEC = true
+	query.Flags |= dns.FLAG_CD
+
+	return w.Exchanger.Exchange(query)
+}
+
+// getMaxQueries returns maximum number of queries
+func (w *Walker) getMaxQueries() int {
+	if w.MaxQueries <= 0 {
+		return DEFAULT_MAX_QUERIES
+	}
+
+	return w.MaxQueries
+}
+
+// ////////////////////////////////////////////////////////////////////////////////// //
+
+// addRecords adds NSEC3 records of zone to chain
+func (c *hashChain) addRecords(result *Result, zone string, records dns.Records) {
+	for _, r := range records {
+		nsec3 := r.NSEC3()
+
+		if nsec3 == nil || nsec3.Algorithm != dns.NSEC3_ALG_SHA1 {
+			continue
+		}
+
+		hash, parent, _ := strings.Cut(dns.Normalize(r.Name), ".")
+		hash = strings.ToUpper(hash)
+
+		if parent != zone {
+			continue
+		}
+
+		index, found := slices.BinarySearch(c.owners, hash)
+
+		if !found {
+			c.owners = slices.Insert(c.owners, index, hash)
+		}
+
+		c.next[hash] = strings.ToUpper(nsec3.Next)
+		result.salt, result.iterations = nsec3.Salt, nsec3.Iterations
+	}
+}
+
+// findUncovered returns random name which hash isn't covered by chain
+func (c *hashChain) findUncovered(result *Result, zone string) string {
+	for range MAX_HASH_ATTEMPTS {
+		name := dns.RandomLabel() + "." + zone
+		hash, err := dns.HashName(name, result.salt, result.iterations)
+
+		if err != nil {
+			return ""
+		}
+
+		if !c.covers(hash) {
+			return name
+		}
+	}
+
+	return ""
+}
+
+// covers returns true if given hash is covered by one of NSEC3 records
+func (c *hashChain) covers(hash string) bool {
+	if len(c.owners) == 0 {
+		return false
+	}
+
+	index, found := slices.BinarySearch(c.owners, hash)
+
+	if found {
+		return true
+	}
+
+	// Hash can be covered only by the previous owner or by the last record
+	// in the chain which points to the first one
+	owner := c.owners[(index+len(c.owners)-1)%len(c.owners)]
+
+	return dns.CoversHash(owner, c.next[owner], hash)
+}
+
+// isComplete returns true if chain of hashes is closed
+func (c *hashChain) isComplete() bool {
+	if len(c.owners) == 0 {
+		return false
+	}
+
+	for _, next := range c.next {
+		if c.next[next] == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isSubdomain returns true if name is subdomain of given zone
+func isSubdomain(name, zone string) bool {
+	return strings.HasSuffix(name, "."+zone)
+}
+
+// isType returns function which checks record type
+func isType(rtype int) func(r *dns.Record) bool {
+	return func(r *dns.Record) bool {
+		return r.Type == rtype
+	}
+}